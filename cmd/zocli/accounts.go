@@ -0,0 +1,422 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/format"
+	"github.com/maheshrijal/zocli/internal/store"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// parseGlobalFlags strips global options from args, wherever they appear,
+// and applies them. Everything after a "--" is left untouched.
+func parseGlobalFlags(args []string) ([]string, error) {
+	var rest []string
+	name := ""
+	tz := ""
+	output := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			rest = append(rest, args[i:]...)
+			i = len(args)
+		case arg == "--account" || arg == "-account":
+			if i+1 >= len(args) {
+				return nil, errors.New("--account requires a value")
+			}
+			name = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--account=") || strings.HasPrefix(arg, "-account="):
+			name = arg[strings.Index(arg, "=")+1:]
+		case len(rest) > 0 && rest[0] == "export" && (arg == "--output" || arg == "-output" ||
+			strings.HasPrefix(arg, "--output=") || strings.HasPrefix(arg, "-output=")):
+			// export predates --output and uses it for the destination file.
+			rest = append(rest, arg)
+		case arg == "--output" || arg == "-output" || arg == "-o":
+			if i+1 >= len(args) {
+				return nil, errors.New("--output requires a value")
+			}
+			output = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--output=") || strings.HasPrefix(arg, "-output="):
+			output = arg[strings.Index(arg, "=")+1:]
+		case arg == "--tz" || arg == "-tz":
+			if i+1 >= len(args) {
+				return nil, errors.New("--tz requires a value")
+			}
+			tz = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--tz=") || strings.HasPrefix(arg, "-tz="):
+			tz = arg[strings.Index(arg, "=")+1:]
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("ZOCLI_ACCOUNT"))
+	}
+	if name != "" {
+		if err := setAccount(name); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(tz) != "" {
+		loc, err := zomato.LoadLocation(tz)
+		if err != nil {
+			return nil, err
+		}
+		reportTimezone = loc
+	}
+	parsed, err := format.ParseOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	outputFormat = parsed
+	return rest, nil
+}
+
+// accountSet records that account was chosen with --account or
+// ZOCLI_ACCOUNT rather than left to the registry default.
+var accountSet bool
+
+func setAccount(name string) error {
+	if name != config.DefaultAccount {
+		if err := config.ValidateAccountName(name); err != nil {
+			return err
+		}
+	}
+	account = name
+	accountSet = true
+	return nil
+}
+
+// resolveAccount falls back to the registry's default account when none
+// was given. It reads accounts.json, so it only runs for commands that
+// use an account's config or orders.
+func resolveAccount() error {
+	if accountSet {
+		return nil
+	}
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	return setAccount(accounts.Selected())
+}
+
+// requireAccount fails when the selected account has not been set up yet.
+func requireAccount() error {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	if !accounts.Has(account) {
+		return fmt.Errorf("unknown account %q; run 'zocli auth login --account %s' or 'zocli accounts add %s'", account, account, account)
+	}
+	return nil
+}
+
+func loadAccounts() (config.Accounts, error) {
+	path, err := config.AccountsPath()
+	if err != nil {
+		return config.Accounts{}, err
+	}
+	return config.LoadAccounts(path)
+}
+
+func saveAccounts(accounts config.Accounts) error {
+	path, err := config.AccountsPath()
+	if err != nil {
+		return err
+	}
+	return config.SaveAccounts(path, accounts)
+}
+
+// registerAccount records name in the registry if it is not known yet.
+func registerAccount(name string) error {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	added, err := accounts.Add(name)
+	if err != nil || !added {
+		return err
+	}
+	return saveAccounts(accounts)
+}
+
+func configPath() (string, error) {
+	return config.PathFor(account)
+}
+
+// storePathFor returns where an account keeps orders for a backend.
+func storePathFor(name, backend string) (string, error) {
+	if name == config.DefaultAccount {
+		return store.DefaultPathFor(backend)
+	}
+	dir, err := config.AccountDir(name)
+	if err != nil {
+		return "", err
+	}
+	return store.PathFor(dir, backend)
+}
+
+// selectAccounts resolves an --accounts value: "all" or a comma-separated
+// list of account names.
+func selectAccounts(selection string) ([]string, error) {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(selection) == "all" {
+		return accounts.All(), nil
+	}
+	var names []string
+	for _, name := range strings.Split(selection, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !accounts.Has(name) {
+			return nil, fmt.Errorf("unknown account %q", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// openAccountStore opens an account's order store using the backend
+// selected in its config.
+func openAccountStore(name string) (store.Store, error) {
+	_, cfg, err := loadAccountConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	path, err := storePathFor(name, cfg.Store)
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(cfg.Store, path)
+}
+
+// openAccountStoreForWrite is openAccountStore for commands that write
+// orders. Stored order times are migrated to the account's region timezone
+// first if needed, so new and old orders share a zone.
+func openAccountStoreForWrite(name string) (store.Store, error) {
+	cfgPath, cfg, err := loadAccountConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := openAccountStore(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateTimezone(st, cfgPath, cfg); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// loadAccountsOrders loads and concatenates the orders of several accounts
+// matching q. "all" selects every registered account.
+func loadAccountsOrders(selection string, q store.Query) ([]zomato.Order, error) {
+	names, err := selectAccounts(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []zomato.Order
+	loaded := 0
+	for _, name := range names {
+		st, err := openAccountStore(name)
+		if err != nil {
+			return nil, err
+		}
+		orders, err := st.Query(q)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("account %s: %w", name, err)
+		}
+		loc, err := reportLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", name, err)
+		}
+		all = append(all, zomato.InLocation(orders, loc)...)
+		loaded++
+	}
+	if loaded == 0 {
+		return nil, errors.New("no stored orders yet; run 'zocli sync' first")
+	}
+	return all, nil
+}
+
+func runAccounts(args []string) error {
+	if len(args) == 0 {
+		return runAccountsList(nil)
+	}
+	switch args[0] {
+	case "list":
+		return runAccountsList(args[1:])
+	case "add":
+		return runAccountsAdd(args[1:])
+	case "remove":
+		return runAccountsRemove(args[1:])
+	case "default":
+		return runAccountsDefault(args[1:])
+	case "help", "-h", "--help":
+		cli.PrintAccountsUsage(os.Stdout)
+		return nil
+	default:
+		cli.PrintAccountsUsage(os.Stderr)
+		return fmt.Errorf("unknown accounts command: %s", args[0])
+	}
+}
+
+func runAccountsList(args []string) error {
+	if len(args) > 0 {
+		cli.PrintAccountsUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(args, " "))
+	}
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	selected := accounts.Selected()
+	for _, name := range accounts.All() {
+		marker := " "
+		if name == selected {
+			marker = "*"
+		}
+		dir, err := config.AccountDir(name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %-16s %s\n", marker, name, dir)
+	}
+	if accounts.Sample != "" {
+		dir, err := config.AccountDir(accounts.Sample)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %-16s %s (sample data, not in --accounts all)\n", accounts.Sample, dir)
+	}
+	return nil
+}
+
+func runAccountsAdd(args []string) error {
+	name, err := accountArg(args)
+	if err != nil {
+		return err
+	}
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	added, err := accounts.Add(name)
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("Account %s already exists.\n", name)
+		return nil
+	}
+	if err := saveAccounts(accounts); err != nil {
+		return err
+	}
+	fmt.Printf("Added account %s. Log in with: zocli auth login --account %s\n", name, name)
+	return nil
+}
+
+func runAccountsRemove(args []string) error {
+	fs := flag.NewFlagSet("accounts remove", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	purge := fs.Bool("purge", false, "Also delete the account's config and orders")
+	fs.Usage = func() {
+		cli.PrintAccountsUsage(os.Stderr)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	name, err := accountArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	if name == config.DefaultAccount {
+		return errors.New("the default account cannot be removed")
+	}
+
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	if !accounts.Remove(name) {
+		return fmt.Errorf("unknown account %q", name)
+	}
+	if err := saveAccounts(accounts); err != nil {
+		return err
+	}
+
+	dir, err := config.AccountDir(name)
+	if err != nil {
+		return err
+	}
+	if *purge {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		fmt.Printf("Removed account %s and deleted %s\n", name, dir)
+		return nil
+	}
+	fmt.Printf("Removed account %s. Its data was kept in %s\n", name, dir)
+	return nil
+}
+
+func runAccountsDefault(args []string) error {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		fmt.Println(accounts.Selected())
+		return nil
+	}
+	name, err := accountArg(args)
+	if err != nil {
+		return err
+	}
+	if !accounts.Has(name) {
+		return fmt.Errorf("unknown account %q; add it with 'zocli accounts add %s'", name, name)
+	}
+	accounts.Default = name
+	if name == config.DefaultAccount {
+		accounts.Default = ""
+	}
+	if err := saveAccounts(accounts); err != nil {
+		return err
+	}
+	fmt.Printf("Default account is now %s\n", name)
+	return nil
+}
+
+func accountArg(args []string) (string, error) {
+	if len(args) != 1 {
+		cli.PrintAccountsUsage(os.Stderr)
+		return "", errors.New("expected exactly one account name")
+	}
+	name := strings.TrimSpace(args[0])
+	if name == config.DefaultAccount {
+		return name, nil
+	}
+	return name, config.ValidateAccountName(name)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/names"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// aliasesPath is the alias file, shared by all accounts.
+func aliasesPath() (string, error) {
+	dir, err := config.BaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aliases.json"), nil
+}
+
+// normalizeNames folds restaurant and dish name variants together and
+// applies the user's aliases, for reports that count by name.
+func normalizeNames(orders []zomato.Order) ([]zomato.Order, error) {
+	return applyAliases(orders, false)
+}
+
+// normalizePriceNames is normalizeNames for price tracking: dishes keep
+// their portion-size qualifiers, so "Biryani (Half)" and "Biryani (Full)"
+// get separate prices.
+func normalizePriceNames(orders []zomato.Order) ([]zomato.Order, error) {
+	return applyAliases(orders, true)
+}
+
+func applyAliases(orders []zomato.Order, keepItemQualifiers bool) ([]zomato.Order, error) {
+	path, err := aliasesPath()
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := names.LoadAliases(path)
+	if err != nil {
+		return nil, err
+	}
+	n := names.NewNormalizer(aliases)
+	n.KeepItemQualifiers = keepItemQualifiers
+	return n.Apply(orders), nil
+}
+
+func runAlias(args []string) error {
+	if len(args) == 0 {
+		cli.PrintAliasUsage(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "add":
+		return runAliasAdd(args[1:])
+	case "remove":
+		return runAliasRemove(args[1:])
+	case "list":
+		return runAliasList(args[1:])
+	case "suggest":
+		return runAliasSuggest(args[1:])
+	case "help", "-h", "--help":
+		cli.PrintAliasUsage(os.Stdout)
+		return nil
+	default:
+		cli.PrintAliasUsage(os.Stderr)
+		return fmt.Errorf("unknown alias command: %s", args[0])
+	}
+}
+
+func aliasFlags(name string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintAliasUsage(os.Stderr)
+	}
+	item := fs.Bool("item", false, "Alias a dish name instead of a restaurant")
+	return fs, item
+}
+
+// aliasTable picks the restaurant or item aliases.
+func aliasTable(a *names.Aliases, item bool) (*map[string]string, string) {
+	if item {
+		return &a.Items, "dish"
+	}
+	return &a.Restaurants, "restaurant"
+}
+
+func runAliasAdd(args []string) error {
+	fs, item := aliasFlags("alias add")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		cli.PrintAliasUsage(os.Stderr)
+		return errors.New("alias add needs two names: the variant and the name to count it as")
+	}
+	from, to := fs.Arg(0), fs.Arg(1)
+
+	path, err := aliasesPath()
+	if err != nil {
+		return err
+	}
+	aliases, err := names.LoadAliases(path)
+	if err != nil {
+		return err
+	}
+	table, kind := aliasTable(&aliases, *item)
+	updated, err := names.Add(*table, from, to)
+	if err != nil {
+		return err
+	}
+	*table = updated
+	if err := names.SaveAliases(path, aliases); err != nil {
+		return err
+	}
+	fmt.Printf("The %s %q is now counted as %q\n", kind, strings.TrimSpace(from), strings.TrimSpace(to))
+	return nil
+}
+
+func runAliasRemove(args []string) error {
+	fs, item := aliasFlags("alias remove")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		cli.PrintAliasUsage(os.Stderr)
+		return errors.New("alias remove needs the variant name")
+	}
+	path, err := aliasesPath()
+	if err != nil {
+		return err
+	}
+	aliases, err := names.LoadAliases(path)
+	if err != nil {
+		return err
+	}
+	table, kind := aliasTable(&aliases, *item)
+	updated, ok := names.Remove(*table, fs.Arg(0))
+	if !ok {
+		return fmt.Errorf("no %s alias for %q", kind, fs.Arg(0))
+	}
+	*table = updated
+	if err := names.SaveAliases(path, aliases); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s alias for %q\n", kind, fs.Arg(0))
+	return nil
+}
+
+func runAliasList(args []string) error {
+	fs, _ := aliasFlags("alias list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintAliasUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	path, err := aliasesPath()
+	if err != nil {
+		return err
+	}
+	aliases, err := names.LoadAliases(path)
+	if err != nil {
+		return err
+	}
+	if len(aliases.Restaurants) == 0 && len(aliases.Items) == 0 {
+		fmt.Println("No aliases. Find likely ones with 'zocli alias suggest'.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tCOUNTED AS")
+	fmt.Fprintln(tw, "----\t----\t----------")
+	for _, kind := range []struct {
+		label string
+		m     map[string]string
+	}{{"restaurant", aliases.Restaurants}, {"dish", aliases.Items}} {
+		froms := make([]string, 0, len(kind.m))
+		for from := range kind.m {
+			froms = append(froms, from)
+		}
+		sort.Strings(froms)
+		for _, from := range froms {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", kind.label, from, kind.m[from])
+		}
+	}
+	tw.Flush()
+	fmt.Printf("\nAliases file: %s\n", path)
+	return nil
+}
+
+func runAliasSuggest(args []string) error {
+	fs, item := aliasFlags("alias suggest")
+	minSimilarity := fs.Float64("min", names.DefaultSimilarity, "Minimum similarity (0-1) to suggest")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintAliasUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	orders, err := loadOrders()
+	if err != nil {
+		return err
+	}
+	orders, err = normalizeNames(orders)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, order := range orders {
+		if !*item {
+			counts[order.Restaurant]++
+			continue
+		}
+		for _, it := range order.Items {
+			counts[it.Name] += max(it.Quantity, 1)
+		}
+	}
+	suggestions := names.Suggest(counts, *minSimilarity)
+	if len(suggestions) == 0 {
+		fmt.Println("No similar names found.")
+		return nil
+	}
+	flagArg := ""
+	if *item {
+		flagArg = "--item "
+	}
+	for _, s := range suggestions {
+		fmt.Printf("%.0f%%  %s (%d) → %s (%d)\n", s.Similarity*100, s.From, s.FromCount, s.To, s.ToCount)
+		fmt.Printf("      zocli alias add %s%s %s\n", flagArg, strconv.Quote(s.From), strconv.Quote(s.To))
+	}
+	return nil
+}
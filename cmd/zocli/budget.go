@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/budget"
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/filter"
+	"github.com/maheshrijal/zocli/internal/format"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// errOverBudget fails 'budget status' when a limit is exceeded, so it can
+// gate a cron job.
+var errOverBudget = errors.New("over budget")
+
+func runBudget(args []string) error {
+	if len(args) == 0 {
+		cli.PrintBudgetUsage(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "set":
+		return runBudgetSet(args[1:])
+	case "remove":
+		return runBudgetRemove(args[1:])
+	case "status":
+		return runBudgetStatus(args[1:])
+	case "help", "-h", "--help":
+		cli.PrintBudgetUsage(os.Stdout)
+		return nil
+	default:
+		cli.PrintBudgetUsage(os.Stderr)
+		return fmt.Errorf("unknown budget command: %s", args[0])
+	}
+}
+
+// budgetScope registers the flags that pick which orders a budget covers.
+func budgetScope(fs *flag.FlagSet) func() (budget.Budget, error) {
+	restaurant := fs.String("restaurant", "", "Only count restaurants matching this (substring or /regexp/)")
+	category := fs.String("category", "", "Only count orders in this category, e.g. late-night or weekend")
+	return func() (budget.Budget, error) {
+		b := budget.Budget{Restaurant: strings.TrimSpace(*restaurant)}
+		if *category != "" {
+			c, err := budget.ParseCategory(*category)
+			if err != nil {
+				return b, err
+			}
+			b.Category = c
+		}
+		return b, nil
+	}
+}
+
+func parseBudgetFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintBudgetUsage(os.Stderr)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintBudgetUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	return nil
+}
+
+func runBudgetSet(args []string) error {
+	fs := flag.NewFlagSet("budget set", flag.ContinueOnError)
+	monthly := fs.String("monthly", "", "Monthly limit, e.g. 8000 or \"AED 500\"")
+	scope := budgetScope(fs)
+	if err := parseBudgetFlags(fs, args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*monthly) == "" {
+		cli.PrintBudgetUsage(os.Stderr)
+		return errors.New("--monthly is required")
+	}
+	b, err := scope()
+	if err != nil {
+		return err
+	}
+	limit, err := zomato.ParseMoney(*monthly, "")
+	if err != nil {
+		return fmt.Errorf("invalid --monthly %q: %w", *monthly, err)
+	}
+	if limit.Amount <= 0 {
+		return fmt.Errorf("--monthly must be positive, got %s", *monthly)
+	}
+	b.Monthly = limit
+	if _, err := filter.NewMatcher(b.Restaurant); err != nil {
+		return fmt.Errorf("invalid --restaurant: %w", err)
+	}
+
+	cfgPath, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := config.Update(cfgPath, func(c *config.Config) {
+		c.Budgets = budget.Set(c.Budgets, b)
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Budget for %s set to %s a month\n", b.Name(), b.Monthly)
+	return nil
+}
+
+func runBudgetRemove(args []string) error {
+	fs := flag.NewFlagSet("budget remove", flag.ContinueOnError)
+	scope := budgetScope(fs)
+	if err := parseBudgetFlags(fs, args); err != nil {
+		return err
+	}
+	b, err := scope()
+	if err != nil {
+		return err
+	}
+	cfgPath, cfg, err := loadAccountConfig(account)
+	if err != nil {
+		return err
+	}
+	budgets, ok := budget.Remove(cfg.Budgets, b)
+	if !ok {
+		return fmt.Errorf("no budget for %s", b.Name())
+	}
+	if err := config.Update(cfgPath, func(c *config.Config) {
+		c.Budgets = budgets
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Removed budget for %s\n", b.Name())
+	return nil
+}
+
+func runBudgetStatus(args []string) error {
+	fs := flag.NewFlagSet("budget status", flag.ContinueOnError)
+	currency := addCurrencyFlags(fs)
+	if err := parseBudgetFlags(fs, args); err != nil {
+		return err
+	}
+	_, cfg, err := loadAccountConfig(account)
+	if err != nil {
+		return err
+	}
+	statuses, err := budgetStatuses(cfg.Budgets, currency)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat != format.OutputTable {
+		if err := format.WriteSections(os.Stdout, outputFormat, format.BudgetRecords(statuses)); err != nil {
+			return err
+		}
+	} else {
+		format.BudgetTable(os.Stdout, statuses)
+	}
+	over := 0
+	for _, s := range statuses {
+		if s.Over() {
+			over++
+		}
+	}
+	if over > 0 {
+		return fmt.Errorf("%w: %d of %d budgets exceeded", errOverBudget, over, len(statuses))
+	}
+	return nil
+}
+
+// budgetStatuses evaluates budgets against this month's stored orders in
+// the report timezone. With no budgets set, the store isn't needed.
+func budgetStatuses(budgets []budget.Budget, currency currencyFlags) ([]budget.Status, error) {
+	if len(budgets) == 0 {
+		return nil, nil
+	}
+	orders, err := loadOrders()
+	if err != nil {
+		return nil, err
+	}
+	orders, err = currency.apply(orders)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := reportLocation(account)
+	if err != nil {
+		return nil, err
+	}
+	return budget.EvaluateAll(budgets, orders, time.Now().In(loc))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+
+	"github.com/maheshrijal/zocli/internal/format"
+	"golang.org/x/term"
+)
+
+// chartFlags registers the flags that control how charts are drawn.
+func chartFlags(fs *flag.FlagSet) func() format.ChartStyle {
+	noColor := fs.Bool("no-color", false, "Draw charts without colors")
+	ascii := fs.Bool("ascii", false, "Draw charts with plain ASCII characters")
+	return func() format.ChartStyle {
+		return chartStyle(*noColor, *ascii)
+	}
+}
+
+// chartStyle fits charts to the terminal. Colors are used only on a
+// terminal, and never when NO_COLOR is set.
+func chartStyle(noColor, ascii bool) format.ChartStyle {
+	fd := int(os.Stdout.Fd())
+	tty := term.IsTerminal(fd)
+	style := format.ChartStyle{
+		ASCII: ascii,
+		Color: tty && !noColor && os.Getenv("NO_COLOR") == "",
+	}
+	if width, _, err := term.GetSize(fd); tty && err == nil && width > 0 {
+		style.Width = width
+	} else if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		style.Width = width
+	}
+	return style
+}
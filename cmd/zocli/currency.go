@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// currencyFlags holds the --base and --rates options shared by the
+// commands that summarize spend.
+type currencyFlags struct {
+	base  *string
+	rates *string
+}
+
+func addCurrencyFlags(fs *flag.FlagSet) currencyFlags {
+	return currencyFlags{
+		base:  fs.String("base", "", "Convert all amounts to this currency (e.g. INR)"),
+		rates: fs.String("rates", "", "Exchange rates file (default: rates.json in the zocli config dir)"),
+	}
+}
+
+// code is the --base currency code, or "" without --base.
+func (f currencyFlags) code() string {
+	return strings.ToUpper(strings.TrimSpace(*f.base))
+}
+
+// apply converts orders to the --base currency. Without --base, orders are
+// returned unchanged and summaries are computed per currency.
+func (f currencyFlags) apply(orders []zomato.Order) ([]zomato.Order, error) {
+	base := f.code()
+	if base == "" {
+		return orders, nil
+	}
+	path := *f.rates
+	if path == "" {
+		dir, err := config.BaseDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, "rates.json")
+	}
+	rates, err := stats.LoadRates(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("--base needs an exchange rates file; create %s (see 'zocli help stats')", path)
+		}
+		return nil, err
+	}
+	return rates.ConvertOrders(orders, base)
+}
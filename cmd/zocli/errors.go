@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Exit codes for failures a script may want to react to differently.
+const (
+	exitError         = 1
+	exitUnauthorized  = 3
+	exitRateLimited   = 4
+	exitBlocked       = 5
+	exitSchemaChanged = 6
+	exitOverBudget    = 7
+)
+
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, zomato.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, zomato.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, zomato.ErrBlocked):
+		return exitBlocked
+	case errors.Is(err, zomato.ErrSchemaChanged):
+		return exitSchemaChanged
+	case errors.Is(err, errOverBudget):
+		return exitOverBudget
+	default:
+		return exitError
+	}
+}
+
+// remediation returns a hint telling the user what to do about err, or ""
+// when there is nothing more specific to say than the error itself.
+func remediation(err error) string {
+	switch {
+	case errors.Is(err, zomato.ErrUnauthorized):
+		return fmt.Sprintf("Your Zomato session has expired or was revoked. Run '%s' to sign in again.", accountCommand("zocli auth login"))
+	case errors.Is(err, zomato.ErrRateLimited):
+		return "Zomato is rate limiting requests. Wait a few minutes, then re-run with a larger --page-delay; progress so far has been saved."
+	case errors.Is(err, zomato.ErrBlocked):
+		return fmt.Sprintf("Zomato served a captcha or bot-check page. Open zomato.com in your browser, complete the check, then run '%s' to refresh the cookie.", accountCommand("zocli auth login"))
+	case errors.Is(err, zomato.ErrSchemaChanged):
+		return "Zomato changed its response format and zocli could not parse it. Please report this with the response snippet above at https://github.com/maheshrijal/zocli/issues."
+	default:
+		return ""
+	}
+}
+
+func accountCommand(command string) string {
+	if account == config.DefaultAccount {
+		return command
+	}
+	return command + " --account " + account
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/filter"
+	"github.com/maheshrijal/zocli/internal/store"
+)
+
+// filterOptions resolves the shared filter flags, reading dates in the
+// report timezone and amounts without a currency in currency (the --base
+// currency, if the command has one).
+func filterOptions(flags *filter.Flags, currency string) (filter.Options, error) {
+	loc, err := reportLocation(account)
+	if err != nil {
+		return filter.Options{}, err
+	}
+	return flags.Options(time.Now().In(loc), currency)
+}
+
+// storeQuery is the part of opts the store can answer itself, so commands
+// don't load every order just to drop most of them. The filters are still
+// applied afterwards. Restaurant patterns are only passed down for
+// commands that show names as stored: once aliases rename restaurants, a
+// pattern may match the canonical name but not the stored one.
+func storeQuery(opts filter.Options, storedNames bool) store.Query {
+	q := store.Query{Since: opts.Since, Until: opts.Until, Status: opts.Status}
+	if sub, ok := opts.Restaurant.Substring(); ok && storedNames {
+		q.Restaurant = sub
+	}
+	return q
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/filter"
+	"github.com/maheshrijal/zocli/internal/format"
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func runHeatmap(args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintHeatmapUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("heatmap", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintHeatmapUsage(os.Stderr)
+	}
+	year := fs.Int("year", 0, "Calendar year to show (default: the last 12 months)")
+	accountsFlag := fs.String("accounts", "", "Comma-separated accounts to aggregate, or all")
+	style := chartFlags(fs)
+	filters := filter.Register(fs, false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintHeatmapUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+
+	opts, err := filterOptions(filters, "")
+	if err != nil {
+		return err
+	}
+	var orders []zomato.Order
+	if *accountsFlag != "" {
+		orders, err = loadAccountsOrders(*accountsFlag, storeQuery(opts, false))
+	} else {
+		orders, err = queryOrders(storeQuery(opts, false))
+	}
+	if err != nil {
+		return err
+	}
+	orders, err = normalizeNames(orders)
+	if err != nil {
+		return err
+	}
+	orders = opts.Apply(orders)
+
+	loc, err := reportLocation(account)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(loc)
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+	start, end := tomorrow.AddDate(-1, 0, 0), tomorrow
+	title := "the last 12 months"
+	if *year != 0 {
+		if *year < 2000 || *year > 9999 {
+			return fmt.Errorf("invalid --year %d", *year)
+		}
+		start = time.Date(*year, 1, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(1, 0, 0)
+		title = fmt.Sprint(*year)
+	}
+	days := stats.OrdersPerDay(orders, start, end, loc)
+
+	if outputFormat != format.OutputTable {
+		return format.WriteSections(os.Stdout, outputFormat, format.DayRecords(days))
+	}
+
+	total, active := 0, 0
+	var busiest stats.DayCount
+	for _, d := range days {
+		total += d.Count
+		if d.Count > 0 {
+			active++
+		}
+		if d.Count > busiest.Count {
+			busiest = d
+		}
+	}
+	fmt.Fprintf(os.Stdout, "%d orders on %d of %d days in %s\n\n", total, active, len(days), title)
+	format.Heatmap(os.Stdout, days, style())
+	if busiest.Count > 0 {
+		noun := "orders"
+		if busiest.Count == 1 {
+			noun = "order"
+		}
+		fmt.Fprintf(os.Stdout, "\nBusiest day: %s (%d %s)\n", busiest.Date.Format("Mon 2 Jan 2006"), busiest.Count, noun)
+	}
+	return nil
+}
@@ -6,7 +6,10 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +17,8 @@ import (
 	"github.com/maheshrijal/zocli/internal/cli"
 	"github.com/maheshrijal/zocli/internal/config"
 	"github.com/maheshrijal/zocli/internal/export"
+	"github.com/maheshrijal/zocli/internal/fileutil"
+	"github.com/maheshrijal/zocli/internal/filter"
 	"github.com/maheshrijal/zocli/internal/format"
 	"github.com/maheshrijal/zocli/internal/sample"
 	"github.com/maheshrijal/zocli/internal/stats"
@@ -26,25 +31,39 @@ import (
 
 var version = "dev"
 
+// account is the account selected with --account or ZOCLI_ACCOUNT, falling
+// back to the registry default once resolveAccount has run.
+var account = config.DefaultAccount
+
+// outputFormat is the global --output format; format.OutputTable prints the
+// usual human-readable tables.
+var outputFormat = format.OutputTable
+
 func main() {
-	if len(os.Args) < 2 {
+	args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	if len(args) < 1 {
 		cli.PrintUsage(os.Stdout)
 		os.Exit(1)
 	}
 
-	if len(os.Args) > 2 && (os.Args[2] == "help" || os.Args[2] == "-h" || os.Args[2] == "--help") {
-		if !cli.PrintCommandUsage(os.Stdout, os.Args[1]) {
-			fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", os.Args[1])
+	if len(args) > 1 && (args[1] == "help" || args[1] == "-h" || args[1] == "--help") {
+		if !cli.PrintCommandUsage(os.Stdout, args[0]) {
+			fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", args[0])
 			cli.PrintUsage(os.Stderr)
 			os.Exit(1)
 		}
 		return
 	}
 
-	if os.Args[1] == "help" {
-		if len(os.Args) > 2 {
-			if !cli.PrintCommandUsage(os.Stdout, os.Args[2]) {
-				fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", os.Args[2])
+	if args[0] == "help" {
+		if len(args) > 1 {
+			if !cli.PrintCommandUsage(os.Stdout, args[1]) {
+				fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", args[1])
 				cli.PrintUsage(os.Stderr)
 				os.Exit(1)
 			}
@@ -54,35 +73,58 @@ func main() {
 		return
 	}
 
-	switch os.Args[1] {
+	switch args[0] {
+	case "help", "-h", "--help", "version", "-v", "--version":
+	case "auth", "accounts":
+		must(resolveAccount())
+	default:
+		must(resolveAccount())
+		must(requireAccount())
+	}
+
+	switch args[0] {
 	case "help", "-h", "--help":
 		cli.PrintUsage(os.Stdout)
 	case "version", "-v", "--version":
 		fmt.Println(version)
 	case "auth":
-		must(runAuth(os.Args[2:]))
+		must(runAuth(args[1:]))
+	case "accounts":
+		must(runAccounts(args[1:]))
 	case "sync":
-		must(runSync(os.Args[2:]))
+		must(runSync(args[1:]))
 	case "orders":
-		must(runOrders(os.Args[2:]))
+		must(runOrders(args[1:]))
 	case "stats":
-		must(runStats(os.Args[2:]))
+		must(runStats(args[1:]))
 	case "config":
-		must(runConfig(os.Args[2:]))
+		must(runConfig(args[1:]))
+	case "store":
+		must(runStore(args[1:]))
 	case "inflation":
-		must(runInflation(os.Args[2:]))
-	case "debug-api":
-		must(runDebugAPI(os.Args[2:]))
+		must(runInflation(args[1:]))
+	case "query":
+		must(runQuery(args[1:]))
+	case "budget":
+		must(runBudget(args[1:]))
+	case "alias":
+		must(runAlias(args[1:]))
+	case "tag":
+		must(runTag(args[1:]))
+	case "reparse":
+		must(runReparse(args[1:]))
 	case "dash":
-		must(runDash(os.Args[2:]))
+		must(runDash(args[1:]))
 	case "export":
-		must(runExport(os.Args[2:]))
+		must(runExport(args[1:]))
 	case "suggest":
-		must(runSuggest(os.Args[2:]))
+		must(runSuggest(args[1:]))
 	case "wrapped":
-		must(runWrapped(os.Args[2:]))
+		must(runWrapped(args[1:]))
+	case "heatmap":
+		must(runHeatmap(args[1:]))
 	default:
-		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", os.Args[1])
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", args[0])
 		cli.PrintUsage(os.Stderr)
 		os.Exit(1)
 	}
@@ -99,6 +141,8 @@ func runAuth(args []string) error {
 			return runAuthLogout(args[1:])
 		case "status":
 			return runAuthStatus(args[1:])
+		case "secrets":
+			return runAuthSecrets(args[1:])
 		case "help", "-h", "--help":
 			cli.PrintAuthUsage(os.Stdout)
 			return nil
@@ -142,17 +186,17 @@ func runAuth(args []string) error {
 		return errors.New("cookie value is required; use 'auth login' or --cookie/--cookie-file")
 	}
 
-	cfgPath, err := config.DefaultPath()
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
 
-	if err := config.Save(cfgPath, config.Config{Cookie: value}); err != nil {
+	if err := config.SaveCookie(cfgPath, value, secretOptions()); err != nil {
 		return err
 	}
 
-	fmt.Printf("Saved cookie to %s\n", cfgPath)
-	return nil
+	fmt.Printf("Saved cookie to %s\n", config.SecretLocation(cfgPath))
+	return registerAccount(account)
 }
 
 func runAuthLogin(args []string) error {
@@ -191,18 +235,23 @@ func runAuthLogin(args []string) error {
 		*profile = "Default"
 	}
 
-	cfgPath, err := config.DefaultPath()
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
 
-	return auth.LoginAndSaveCookieWithOptions(context.Background(), cfgPath, auth.LoginOptions{
+	err = auth.LoginAndSaveCookieWithOptions(context.Background(), cfgPath, auth.LoginOptions{
 		Headless:    *headless,
 		Browser:     *browser,
 		BrowserPath: *browserPath,
 		UserDataDir: *userDataDir,
 		ProfileDir:  *profile,
+		Secrets:     secretOptions(),
 	})
+	if err != nil {
+		return err
+	}
+	return registerAccount(account)
 }
 
 func runAuthStatus(args []string) error {
@@ -220,19 +269,15 @@ func runAuthStatus(args []string) error {
 		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
 	}
 
-	cfgPath, err := config.DefaultPath()
-	if err != nil {
-		return err
-	}
-	cfg, err := config.Load(cfgPath)
+	cookie, err := loadCookie()
 	if err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.Is(err, config.ErrSecretNotFound) {
 			fmt.Println("Not logged in (no saved cookie). Run `zocli auth login`.")
 			return nil
 		}
 		return err
 	}
-	if strings.TrimSpace(cfg.Cookie) == "" {
+	if strings.TrimSpace(cookie) == "" {
 		fmt.Println("Not logged in (empty cookie). Run `zocli auth login`.")
 		return nil
 	}
@@ -241,7 +286,7 @@ func runAuthStatus(args []string) error {
 		return nil
 	}
 
-	client := zomato.NewClient(cfg.Cookie)
+	client := zomato.NewClient(cookie)
 	ok, err := client.CheckAuth(context.Background())
 	if err != nil {
 		return err
@@ -268,12 +313,12 @@ func runAuthLogout(args []string) error {
 		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
 	}
 
-	cfgPath, err := config.DefaultPath()
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
 
-	if err := config.Save(cfgPath, config.Config{Cookie: ""}); err != nil {
+	if err := config.DeleteCookie(cfgPath, secretOptions()); err != nil {
 		return err
 	}
 	fmt.Println("Logged out (saved cookie cleared).")
@@ -304,18 +349,23 @@ func runAuthImport(args []string) error {
 
 	fmt.Println("If import fails, close the browser and try again.")
 
-	cfgPath, err := config.DefaultPath()
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
 
-	return auth.ImportFromBrowser(context.Background(), cfgPath, auth.LoginOptions{
+	err = auth.ImportFromBrowser(context.Background(), cfgPath, auth.LoginOptions{
 		Headless:    *headless,
 		Browser:     *browser,
 		BrowserPath: *browserPath,
 		UserDataDir: *userDataDir,
 		ProfileDir:  *profile,
+		Secrets:     secretOptions(),
 	})
+	if err != nil {
+		return err
+	}
+	return registerAccount(account)
 }
 
 func runSync(args []string) error {
@@ -329,6 +379,11 @@ func runSync(args []string) error {
 		cli.PrintSyncUsage(os.Stderr)
 	}
 	mock := fs.Bool("mock", false, "Use sample data instead of hitting Zomato")
+	full := fs.Bool("full", false, "Fetch the complete order history instead of only new orders")
+	details := fs.Bool("details", false, "Also fetch item prices, taxes and fees for orders without details")
+	retries := fs.Int("retries", zomato.DefaultRetryPolicy.MaxRetries, "Retries per request on rate limits, server errors and timeouts")
+	pageDelay := fs.Duration("page-delay", zomato.DefaultPageDelay, "Pause between page requests")
+	noArchive := fs.Bool("no-archive", false, "Don't keep the raw API responses for 'zocli reparse'")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -337,41 +392,66 @@ func runSync(args []string) error {
 		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
 	}
 
-	storePath, err := store.DefaultPath()
+	if *mock {
+		return syncMock()
+	}
+
+	st, err := openStoreForWrite()
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
+	loc, err := accountLocation(account)
 	if err != nil {
 		return err
 	}
 
-	if *mock {
-		orders, err := sample.Orders()
-		if err != nil {
-			return err
-		}
-		if err := st.Save(orders); err != nil {
-			return err
-		}
-		fmt.Printf("Stored %d sample orders in %s\n", len(orders), storePath)
-		return nil
+	cookie, err := loadCookie()
+	if err != nil && !os.IsNotExist(err) && !errors.Is(err, config.ErrSecretNotFound) {
+		return fmt.Errorf("load cookie: %w", err)
+	}
+	if strings.TrimSpace(cookie) == "" {
+		return errors.New("no cookie found; run 'zocli auth login' first")
 	}
 
-	cfgPath, err := config.DefaultPath()
-	if err != nil {
+	existing, err := st.Load()
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	cfg, err := config.Load(cfgPath)
+
+	statePath := store.SyncStatePath(st.Path())
+	state, err := store.LoadSyncState(statePath)
 	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+		return fmt.Errorf("load sync state: %w", err)
 	}
-	if strings.TrimSpace(cfg.Cookie) == "" {
-		return errors.New("no cookie found; run 'zocli auth login' first")
+	startPage := 1
+	if state.NextPage > 1 && (state.Full || !*full) {
+		startPage = state.NextPage
+		*full = state.Full
+		fmt.Fprintf(os.Stdout, "Resuming interrupted sync from page %d\n", startPage)
 	}
 
-	client := zomato.NewClient(cfg.Cookie)
+	known := map[string]struct{}{}
+	if !*full {
+		for _, order := range existing {
+			known[order.ID] = struct{}{}
+		}
+	}
+
+	client := zomato.NewClient(cookie)
+	client.Retry.MaxRetries = max(*retries, 0)
+	client.PageDelay = *pageDelay
+	client.Location = loc
+	if !*noArchive {
+		client.OnRaw = archiveRecorder(st.Path())
+	}
 	terminal := isTerminal(os.Stdout)
+	client.OnRetry = func(e zomato.RetryEvent) {
+		if terminal {
+			fmt.Fprintln(os.Stdout)
+		}
+		fmt.Fprintf(os.Stderr, "Request failed (%s); retry %d/%d in %s\n",
+			e.Reason, e.Attempt, client.Retry.MaxRetries, e.Wait.Round(100*time.Millisecond))
+	}
 	progress := func(p zomato.FetchProgress) {
 		total := "?"
 		if p.TotalPages > 0 {
@@ -383,20 +463,130 @@ func runSync(args []string) error {
 			fmt.Fprintf(os.Stdout, "Fetched page %d/%s (orders: %d)\n", p.Page, total, p.TotalOrders)
 		}
 	}
-	orders, err := client.FetchOrdersWithProgress(context.Background(), progress)
+	fetched, fetchErr := client.FetchOrdersWithOptions(context.Background(), zomato.FetchOptions{
+		Known:     known,
+		StartPage: startPage,
+		Progress:  progress,
+	})
+	if terminal {
+		fmt.Fprintln(os.Stdout)
+	}
+	result, err := st.Merge(fetched)
 	if err != nil {
 		return err
 	}
-	if terminal {
-		fmt.Fprintln(os.Stdout)
+	cfgPath, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := recordOrdersTimezone(cfgPath, loc); err != nil {
+		return err
+	}
+	printMergeResult(result, st.Path())
+
+	if fetchErr != nil {
+		var pageErr *zomato.PageError
+		if !errors.As(fetchErr, &pageErr) {
+			return fetchErr
+		}
+		state := store.SyncState{NextPage: pageErr.Page, Full: *full, Error: pageErr.Err.Error()}
+		if err := store.SaveSyncState(statePath, state); err != nil {
+			return fmt.Errorf("%w (saving sync state also failed: %v)", fetchErr, err)
+		}
+		return fmt.Errorf("sync stopped at %w; re-run 'zocli sync' to resume", fetchErr)
 	}
-	if err := st.Save(orders); err != nil {
+	if err := store.ClearSyncState(statePath); err != nil {
 		return err
 	}
-	fmt.Printf("Stored %d orders in %s\n", len(orders), storePath)
+	if *details {
+		return syncDetails(client, st, terminal)
+	}
 	return nil
 }
 
+// mockAccount keeps the orders of 'sync --mock' apart from real history.
+// It is registered as the sample account, which --accounts all skips.
+const mockAccount = "sample"
+
+func syncMock() error {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	if accounts.Sample != mockAccount {
+		if accounts.Has(mockAccount) {
+			return fmt.Errorf("account %q already exists and was not created by 'sync --mock'; not adding sample orders to it", mockAccount)
+		}
+		if _, err := accounts.Add(mockAccount); err != nil {
+			return err
+		}
+		accounts.Sample = mockAccount
+		if err := saveAccounts(accounts); err != nil {
+			return err
+		}
+	}
+	st, err := openAccountStoreForWrite(mockAccount)
+	if err != nil {
+		return err
+	}
+	cfgPath, cfg, err := loadAccountConfig(mockAccount)
+	if err != nil {
+		return err
+	}
+	loc, err := zomato.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return err
+	}
+	orders, err := sample.Orders()
+	if err != nil {
+		return err
+	}
+	result, err := st.Merge(zomato.Rezone(orders, loc))
+	if err != nil {
+		return err
+	}
+	if err := recordOrdersTimezone(cfgPath, loc); err != nil {
+		return err
+	}
+	printMergeResult(result, st.Path())
+	fmt.Fprintf(os.Stdout, "Sample orders are kept in the %q account; try 'zocli --account %s stats'\n", mockAccount, mockAccount)
+	return nil
+}
+
+func syncDetails(client *zomato.Client, st store.Store, terminal bool) error {
+	orders, err := st.Load()
+	if err != nil {
+		return err
+	}
+	progress := func(done, total int) {
+		if terminal {
+			fmt.Fprintf(os.Stdout, "\rFetched details %d/%d", done, total)
+		} else {
+			fmt.Fprintf(os.Stdout, "Fetched details %d/%d\n", done, total)
+		}
+	}
+	enriched, fetchErr := client.FetchDetails(context.Background(), orders, progress)
+	if terminal && len(enriched) > 0 {
+		fmt.Fprintln(os.Stdout)
+	}
+	// Keep whatever was fetched before a failure so the next run skips it.
+	if len(enriched) > 0 {
+		if _, err := st.Merge(enriched); err != nil {
+			return err
+		}
+	}
+	if fetchErr != nil {
+		return fmt.Errorf("fetch order details: %w", fetchErr)
+	}
+	fmt.Printf("Fetched details for %d orders\n", len(enriched))
+	return nil
+}
+
+func printMergeResult(result store.MergeResult, storePath string) {
+	fmt.Printf("Added %d, updated %d, unchanged %d; %d orders stored in %s\n",
+		result.Added, result.Updated, result.Unchanged, result.Total, storePath)
+}
+
 func runOrders(args []string) error {
 	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
 		cli.PrintOrdersUsage(os.Stdout)
@@ -405,6 +595,7 @@ func runOrders(args []string) error {
 	fs := flag.NewFlagSet("orders", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	limit := fs.Int("limit", 20, "Max orders to print")
+	filters := filter.Register(fs, true)
 	fs.Usage = func() {
 		cli.PrintOrdersUsage(os.Stderr)
 	}
@@ -416,23 +607,18 @@ func runOrders(args []string) error {
 		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
 	}
 
-	storePath, err := store.DefaultPath()
-	if err != nil {
-		return err
-	}
-	st, err := store.New(storePath)
+	opts, err := filterOptions(filters, "")
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	opts.Limit = *limit
+	orders, err := queryOrders(storeQuery(opts, true))
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
-	if *limit > 0 && len(orders) > *limit {
-		orders = orders[:*limit]
+	orders = opts.Apply(orders)
+	if outputFormat != format.OutputTable {
+		return format.WriteSections(os.Stdout, outputFormat, format.OrderRecords(orders))
 	}
 	format.OrdersTable(os.Stdout, orders)
 	return nil
@@ -446,8 +632,14 @@ func runStats(args []string) error {
 	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	group := fs.String("group", "month", "Group by: none, month, year")
-	view := fs.String("view", "basic", "View: basic, spend, patterns, personal, all")
+	view := fs.String("view", "basic", "View: basic, spend, patterns, personal, forecast, anomalies, categories, all")
 	top := fs.Int("top", 5, "Top N restaurants/items")
+	threshold := fs.Float64("threshold", stats.DefaultAnomalyThreshold, "Anomaly score to flag (higher flags fewer)")
+	accountsFlag := fs.String("accounts", "", "Comma-separated accounts to aggregate, or all")
+	chart := fs.Bool("chart", false, "Draw bar charts instead of tables")
+	chartStyle := chartFlags(fs)
+	currency := addCurrencyFlags(fs)
+	filters := filter.Register(fs, false)
 	fs.Usage = func() {
 		cli.PrintStatsUsage(os.Stderr)
 	}
@@ -458,20 +650,28 @@ func runStats(args []string) error {
 		cli.PrintStatsUsage(os.Stderr)
 		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
 	}
+	if *chart && outputFormat != format.OutputTable {
+		return errors.New("--chart only works with --output table")
+	}
 
-	storePath, err := store.DefaultPath()
+	opts, err := filterOptions(filters, currency.code())
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
-	if err != nil {
-		return err
+	var orders []zomato.Order
+	if *accountsFlag != "" {
+		orders, err = loadAccountsOrders(*accountsFlag, storeQuery(opts, false))
+		if err != nil {
+			return err
+		}
+	} else {
+		orders, err = queryOrders(storeQuery(opts, false))
+		if err != nil {
+			return err
+		}
 	}
-	orders, err := st.Load()
+	orders, err = normalizeNames(orders)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
 
@@ -480,58 +680,243 @@ func runStats(args []string) error {
 		viewKey = "basic"
 	}
 	switch viewKey {
-	case "basic", "all", "spend", "patterns", "personal":
+	case "basic", "all", "spend", "patterns", "personal", "forecast", "anomalies", "categories":
 	default:
 		cli.PrintStatsUsage(os.Stderr)
 		return fmt.Errorf("unknown view: %s", viewKey)
 	}
 
-	summary := stats.ComputeSummary(orders)
+	// Convert first so --min and --max compare amounts in the --base currency.
+	orders, err = currency.apply(orders)
+	if err != nil {
+		return err
+	}
+	orders = opts.Apply(orders)
 
 	showBasic := viewKey == "basic"
 	showSpend := viewKey == "all" || viewKey == "spend"
 	showPatterns := viewKey == "all" || viewKey == "patterns"
 	showPersonal := viewKey == "all" || viewKey == "personal"
+	showForecast := viewKey == "all" || viewKey == "forecast"
+	showAnomalies := viewKey == "all" || viewKey == "anomalies"
+	showCategories := viewKey == "all" || viewKey == "categories"
 
-	if showBasic || showSpend {
-		groups, err := stats.GroupOrders(orders, *group)
+	var tagsOf func(zomato.Order) []string
+	if showCategories {
+		tagAccounts := []string{account}
+		if *accountsFlag != "" {
+			tagAccounts, err = selectAccounts(*accountsFlag)
+			if err != nil {
+				return err
+			}
+		}
+		tagger, err := newTagger(tagAccounts...)
 		if err != nil {
 			return err
 		}
-		format.StatsGroups(os.Stdout, groups, summary.Currency)
-		fmt.Fprintln(os.Stdout)
-		format.StatsSummary(os.Stdout, summary)
+		tagsOf = tagger.Tags
+	}
+
+	loc, err := reportLocation(account)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(loc)
+	style := chartStyle()
+
+	// Amounts in different currencies are never added together; each
+	// currency gets its own tables unless --base converted them.
+	currencies := stats.SplitByCurrency(orders)
+	if len(currencies) == 0 {
+		currencies = []stats.CurrencyGroup{{}}
+	}
+	if outputFormat != format.OutputTable {
+		var sections []format.Section
+		if showBasic || showSpend {
+			var groups []stats.Group
+			for _, cur := range currencies {
+				curGroups, err := stats.GroupOrders(cur.Orders, *group)
+				if err != nil {
+					return err
+				}
+				groups = append(groups, curGroups...)
+			}
+			sections = append(sections, format.GroupRecords(groups), format.SummaryRecords(stats.ComputeSummaries(orders)))
+		}
+		if showSpend {
+			var spend []stats.SpendBucket
+			for _, cur := range currencies {
+				spend = append(spend, stats.SpendByWeekday(cur.Orders)...)
+			}
+			sections = append(sections, format.SpendRecords(spend))
+		}
+		if showForecast {
+			var forecasts []stats.Forecast
+			for _, cur := range currencies {
+				f, err := stats.ForecastNextMonth(cur.Orders, now)
+				if errors.Is(err, stats.ErrNotEnoughHistory) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				forecasts = append(forecasts, f)
+			}
+			sections = append(sections, format.ForecastRecords(forecasts))
+		}
+		if showAnomalies {
+			var anomalies []stats.Anomaly
+			for _, cur := range currencies {
+				anomalies = append(anomalies, stats.DetectAnomalies(cur.Orders, now, *threshold)...)
+			}
+			sections = append(sections, format.AnomalyRecords(anomalies))
+		}
+		if showCategories {
+			var totals []stats.TagTotal
+			var periods []stats.TagPeriod
+			for _, cur := range currencies {
+				totals = append(totals, stats.SpendByTag(cur.Orders, tagsOf)...)
+				curPeriods, err := stats.SpendByTagOverTime(cur.Orders, tagsOf, *group)
+				if err != nil {
+					return err
+				}
+				periods = append(periods, curPeriods...)
+			}
+			sections = append(sections, format.CategoryRecords(totals), format.CategoryPeriodRecords(periods))
+		}
+		if showPatterns {
+			sections = append(sections,
+				format.BucketRecords("weekdays", "weekday", "orders", stats.OrdersByWeekday(orders)),
+				format.BucketRecords("time_windows", "window", "orders", stats.OrdersByTimeWindow(orders)))
+		}
+		if showPersonal {
+			sections = append(sections,
+				format.BucketRecords("top_restaurants", "restaurant", "orders", stats.TopRestaurants(orders, *top)),
+				format.BucketRecords("top_items", "item", "quantity", stats.TopItems(orders, *top)))
+		}
+		return format.WriteSections(os.Stdout, outputFormat, sections...)
+	}
+
+	for i, cur := range currencies {
+		if len(currencies) > 1 {
+			if i > 0 {
+				fmt.Fprintln(os.Stdout)
+			}
+			fmt.Fprintf(os.Stdout, "== %s (%d orders) ==\n\n", cur.Currency, len(cur.Orders))
+		}
+		if showBasic || showSpend {
+			groups, err := stats.GroupOrders(cur.Orders, *group)
+			if err != nil {
+				return err
+			}
+			if *chart {
+				format.ChartGroups(os.Stdout, groups, style)
+			} else {
+				format.StatsGroups(os.Stdout, groups)
+			}
+			fmt.Fprintln(os.Stdout)
+			format.StatsSummary(os.Stdout, stats.ComputeSummary(cur.Orders))
+		}
+		if showSpend {
+			fmt.Fprintln(os.Stdout)
+			fmt.Fprintln(os.Stdout, "Spend by weekday")
+			if *chart {
+				format.ChartSpendByWeekday(os.Stdout, stats.SpendByWeekday(cur.Orders), style)
+			} else {
+				format.StatsSpendByWeekday(os.Stdout, stats.SpendByWeekday(cur.Orders))
+			}
+		}
+		if showForecast {
+			if showSpend {
+				fmt.Fprintln(os.Stdout)
+			}
+			fmt.Fprintln(os.Stdout, "Next month forecast")
+			f, err := stats.ForecastNextMonth(cur.Orders, now)
+			switch {
+			case errors.Is(err, stats.ErrNotEnoughHistory):
+				fmt.Fprintln(os.Stdout, "Not enough history yet: a forecast needs 3 complete months of orders.")
+			case err != nil:
+				return err
+			default:
+				format.StatsForecast(os.Stdout, f)
+			}
+		}
+		if showAnomalies {
+			if showSpend || showForecast {
+				fmt.Fprintln(os.Stdout)
+			}
+			fmt.Fprintln(os.Stdout, "Unusual spend")
+			format.StatsAnomalies(os.Stdout, stats.DetectAnomalies(cur.Orders, now, *threshold))
+		}
+		if showCategories {
+			if showSpend || showForecast || showAnomalies {
+				fmt.Fprintln(os.Stdout)
+			}
+			totals := stats.SpendByTag(cur.Orders, tagsOf)
+			fmt.Fprintln(os.Stdout, "Spend by tag")
+			if *chart {
+				format.ChartCategories(os.Stdout, totals, style)
+			} else {
+				format.StatsCategories(os.Stdout, totals)
+			}
+			periods, err := stats.SpendByTagOverTime(cur.Orders, tagsOf, *group)
+			if err != nil {
+				return err
+			}
+			var shown []string
+			for _, t := range totals {
+				if len(shown) < *top {
+					shown = append(shown, t.Tag)
+				}
+			}
+			fmt.Fprintln(os.Stdout)
+			fmt.Fprintf(os.Stdout, "Top %d tags over time\n", len(shown))
+			format.StatsCategoryTrend(os.Stdout, periods, shown)
+		}
 	}
 
 	if showBasic {
 		fmt.Fprintln(os.Stdout)
-		fmt.Fprintln(os.Stdout, "More views: zocli stats --view spend | patterns | personal")
+		fmt.Fprintln(os.Stdout, "More views: zocli stats --view spend | patterns | personal | forecast | anomalies | categories")
 		return nil
 	}
 
-	if showSpend {
-		fmt.Fprintln(os.Stdout)
-		fmt.Fprintln(os.Stdout, "Spend by weekday")
-		format.StatsSpendByWeekday(os.Stdout, stats.SpendByWeekday(orders), summary.Currency)
+	if showSpend || showForecast || showAnomalies || showCategories {
 		fmt.Fprintln(os.Stdout)
 	}
 
 	if showPatterns {
 		fmt.Fprintln(os.Stdout, "Ordering patterns")
-		format.StatsWeekdayOrders(os.Stdout, stats.OrdersByWeekday(orders))
-		fmt.Fprintln(os.Stdout)
-		format.StatsTimeWindows(os.Stdout, stats.OrdersByTimeWindow(orders))
+		if *chart {
+			format.ChartBuckets(os.Stdout, stats.OrdersByWeekday(orders), style)
+			fmt.Fprintln(os.Stdout)
+			format.ChartBuckets(os.Stdout, stats.OrdersByTimeWindow(orders), style)
+		} else {
+			format.StatsWeekdayOrders(os.Stdout, stats.OrdersByWeekday(orders))
+			fmt.Fprintln(os.Stdout)
+			format.StatsTimeWindows(os.Stdout, stats.OrdersByTimeWindow(orders))
+		}
 		fmt.Fprintln(os.Stdout)
 	}
 
 	if showPersonal {
 		fmt.Fprintln(os.Stdout, "Personal stats")
-		format.StatsTopList(os.Stdout, "Restaurant", stats.TopRestaurants(orders, *top))
+		restaurants := stats.TopRestaurants(orders, *top)
+		if *chart {
+			fmt.Fprintln(os.Stdout, "Top restaurants")
+			format.ChartBuckets(os.Stdout, restaurants, style)
+		} else {
+			format.StatsTopList(os.Stdout, "Restaurant", restaurants)
+		}
 		fmt.Fprintln(os.Stdout)
 		items := stats.TopItems(orders, *top)
-		if len(items) == 0 {
+		switch {
+		case len(items) == 0:
 			fmt.Fprintln(os.Stdout, "No item data to display.")
-		} else {
+		case *chart:
+			fmt.Fprintln(os.Stdout, "Top items")
+			format.ChartBuckets(os.Stdout, items, style)
+		default:
 			format.StatsTopList(os.Stdout, "Item", items)
 		}
 		fmt.Fprintln(os.Stdout)
@@ -540,124 +925,471 @@ func runStats(args []string) error {
 }
 
 func runConfig(args []string) error {
-	if len(args) > 0 {
-		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
-			cli.PrintConfigUsage(os.Stdout)
-			return nil
-		}
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintConfigUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
 		cli.PrintConfigUsage(os.Stderr)
-		return fmt.Errorf("unknown arguments: %s", strings.Join(args, " "))
 	}
-	cfgPath, err := config.DefaultPath()
+	timezone := fs.String("timezone", "", "Set the account's region timezone (IANA name, e.g. Asia/Dubai)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintConfigUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
-	storePath, err := store.DefaultPath()
+	if *timezone != "" {
+		loc, err := zomato.LoadLocation(*timezone)
+		if err != nil {
+			return err
+		}
+		if err := config.Update(cfgPath, func(c *config.Config) {
+			c.Timezone = loc.String()
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("Timezone set to %s\n", loc)
+	}
+	// Opening the store for writing re-zones stored orders after a
+	// timezone change.
+	st, err := openStoreForWrite()
+	if err != nil {
+		return err
+	}
+	loc, err := accountLocation(account)
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Config: %s\n", cfgPath)
-	fmt.Printf("Orders: %s\n", storePath)
+	fmt.Printf("Orders: %s\n", st.Path())
+	fmt.Printf("Timezone: %s\n", loc)
 	return nil
 }
 
-func runInflation(args []string) error {
-	storePath, err := store.DefaultPath()
+// openStore opens the selected account's order store.
+func openStore() (store.Store, error) {
+	return openAccountStore(account)
+}
+
+// openStoreForWrite opens the selected account's order store for a command
+// that writes orders.
+func openStoreForWrite() (store.Store, error) {
+	return openAccountStoreForWrite(account)
+}
+
+func runStore(args []string) error {
+	if len(args) == 0 {
+		cli.PrintStoreUsage(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "restore":
+		return runStoreRestore(args[1:])
+	case "migrate":
+		return runStoreMigrate(args[1:])
+	case "help", "-h", "--help":
+		cli.PrintStoreUsage(os.Stdout)
+		return nil
+	default:
+		cli.PrintStoreUsage(os.Stderr)
+		return fmt.Errorf("unknown store command: %s", args[0])
+	}
+}
+
+func runStoreRestore(args []string) error {
+	fs := flag.NewFlagSet("store restore", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	generation := fs.Int("generation", 0, "Backup generation to restore (1 = most recent)")
+	cfgFile := fs.Bool("config", false, "Restore config.json instead of orders.json")
+	fs.Usage = func() {
+		cli.PrintStoreUsage(os.Stderr)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintStoreUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+
+	if *cfgFile {
+		cfgPath, err := configPath()
+		if err != nil {
+			return err
+		}
+		if *generation == 0 {
+			backups, err := config.Backups(cfgPath)
+			if err != nil {
+				return err
+			}
+			format.BackupsTable(os.Stdout, backups)
+			return nil
+		}
+		if err := config.Restore(cfgPath, *generation); err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s from generation %d\n", cfgPath, *generation)
+		return nil
+	}
+
+	opened, err := openStore()
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
+	st, ok := opened.(interface {
+		Backups() ([]fileutil.Backup, error)
+		Restore(generation int) error
+	})
+	if !ok {
+		return fmt.Errorf("%s does not keep file backups", opened.Path())
+	}
+	if *generation == 0 {
+		backups, err := st.Backups()
+		if err != nil {
+			return err
+		}
+		format.BackupsTable(os.Stdout, backups)
+		return nil
+	}
+	if err := st.Restore(*generation); err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s from generation %d\n", opened.Path(), *generation)
+	return nil
+}
+
+func runStoreMigrate(args []string) error {
+	fs := flag.NewFlagSet("store migrate", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	to := fs.String("to", "", "Target backend: json, sqlite")
+	fs.Usage = func() {
+		cli.PrintStoreUsage(os.Stderr)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintStoreUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	target := strings.ToLower(strings.TrimSpace(*to))
+	if target == "" {
+		cli.PrintStoreUsage(os.Stderr)
+		return errors.New("--to is required")
+	}
+
+	cfgPath, err := configPath()
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	src, err := openStoreForWrite()
+	if err != nil {
+		return err
+	}
+	dstPath, err := storePathFor(account, target)
+	if err != nil {
+		return err
+	}
+	if dstPath == src.Path() {
+		fmt.Printf("Already using the %s store at %s\n", target, dstPath)
+		return nil
+	}
+	dst, err := store.Open(target, dstPath)
+	if err != nil {
+		return err
+	}
+
+	orders, err := src.Load()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return errors.New("no stored orders yet; run 'zocli sync' first")
 		}
 		return err
 	}
+	if err := dst.Save(orders); err != nil {
+		return err
+	}
+	if err := config.Update(cfgPath, func(cfg *config.Config) { cfg.Store = target }); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrated %d orders from %s to %s\n", len(orders), src.Path(), dst.Path())
+	fmt.Printf("The old file was left in place; remove it once you are happy with the %s store.\n", target)
+	return nil
+}
+
+func runInflation(args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintInflationUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("inflation", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintInflationUsage(os.Stderr)
+	}
+	index := fs.Bool("index", false, "Show the personal food-inflation index")
+	group := fs.String("group", "month", "Index period: month or year")
+	cpiFile := fs.String("cpi", "", "Reference CPI CSV for --index (default: cpi.csv in the zocli config dir)")
+	top := fs.Int("top", 5, "Number of trends to show (0 for all)")
+	sortBy := fs.String("sort", stats.SortCount, "Trend order: "+strings.Join(stats.TrendSorts, ", "))
+	minPoints := fs.Int("min-points", 2, "Priced orders an item needs to show a trend")
+	minChange := fs.String("min-change", "", "Only trends that changed at least this much, e.g. 10% (or -10% for drops)")
+	compare := fs.Bool("compare", false, "Compare the named dish across restaurants")
+	filters := filter.Register(fs, false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts, err := filterOptions(filters, "")
+	if err != nil {
+		return err
+	}
+	args = fs.Args()
+	if *index && len(args) > 0 {
+		cli.PrintInflationUsage(os.Stderr)
+		return fmt.Errorf("--index doesn't take an item name: %s", strings.Join(args, " "))
+	}
+	if *compare && len(args) == 0 {
+		cli.PrintInflationUsage(os.Stderr)
+		return errors.New("--compare needs an item name")
+	}
+	if *top < 0 {
+		return fmt.Errorf("--top must not be negative, got %d", *top)
+	}
+	if *minPoints < 1 {
+		return fmt.Errorf("--min-points must be at least 1, got %d", *minPoints)
+	}
+	minChangePct := 0.0
+	if *minChange != "" {
+		if minChangePct, err = parsePercent(*minChange); err != nil {
+			return fmt.Errorf("invalid --min-change: %w", err)
+		}
+	}
+	var sortSet, minPointsSet bool
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "sort":
+			sortSet = true
+		case "min-points":
+			minPointsSet = true
+		}
+	})
+
+	orders, err := queryOrders(storeQuery(opts, false))
+	if err != nil {
+		return err
+	}
+	orders, err = normalizePriceNames(orders)
+	if err != nil {
+		return err
+	}
+	orders = opts.Apply(orders)
 
-	// Case 1: Show top 5 items summary if no args
+	if *index {
+		cpi, err := loadCPI(*cpiFile)
+		if err != nil {
+			return err
+		}
+		return runInflationIndex(orders, *group, cpi)
+	}
+
+	trendOpts := stats.TrendOptions{Sort: *sortBy, Limit: *top, MinPoints: *minPoints, MinChange: minChangePct}
+
+	// Case 1: Show the top items summary if no args
 	if len(args) == 0 {
-		trends := stats.FindTopInflationTrends(orders, 5)
-		var summaries []format.InflationSummary
-
-		for _, t := range trends {
-			summaries = append(summaries, format.InflationSummary{
-				ItemName:    t.Key, // Shows "Restaurant - Item"
-				FirstSeen:   t.FirstSeen.Format("2006-01-02"),
-				FirstPrice:  t.FirstPrice,
-				LastPrice:   t.LastPrice,
-				TotalChange: t.TotalChange,
-			})
-		}
-		
+		trends, err := stats.FindInflationTrends(orders, trendOpts)
+		if err != nil {
+			return err
+		}
+		if outputFormat != format.OutputTable {
+			return format.WriteSections(os.Stdout, outputFormat, format.TrendRecords(trends))
+		}
 		fmt.Println("Top Inflation Trends (Restaurant specific)")
-		format.InflationSummaryTable(os.Stdout, summaries)
+		format.InflationSummaryTable(os.Stdout, trendSummaries(trends))
 		fmt.Println("\nTip: Run 'zocli inflation <item name>' for detailed history.")
 		return nil
 	}
 	
 	query := strings.Join(args, " ")
-	if strings.HasPrefix(query, "-") {
-		if query == "--help" || query == "-h" {
-			fmt.Fprintln(os.Stdout, "Usage: zocli inflation [item name]")
-			fmt.Fprintln(os.Stdout, "  No args: Shows trend summary for top 5 most ordered items.")
-			fmt.Fprintln(os.Stdout, "  Arg: Shows detailed price history for matching items.")
-			return nil
+
+	// Case 2: Compare the dish across restaurants
+	if *compare {
+		trendOpts.Query = query
+		if !sortSet {
+			trendOpts.Sort = stats.SortPrice
+		}
+		if !minPointsSet {
+			trendOpts.MinPoints = 1
 		}
+		trends, err := stats.FindInflationTrends(orders, trendOpts)
+		if err != nil {
+			return err
+		}
+		if outputFormat != format.OutputTable {
+			return format.WriteSections(os.Stdout, outputFormat, format.TrendRecords(trends))
+		}
+		fmt.Printf("%q by restaurant\n", query)
+		format.InflationSummaryTable(os.Stdout, trendSummaries(trends))
+		return nil
 	}
 
+	// Case 3: Price history of matching items
 	points, err := stats.CalculateInflation(orders, query)
 	if err != nil {
 		return err
 	}
+	if outputFormat != format.OutputTable {
+		return format.WriteSections(os.Stdout, outputFormat, format.PricePointRecords(points))
+	}
 
 	format.InflationTable(os.Stdout, points)
 	return nil
 }
 
-func runDebugAPI(args []string) error {
-	path, err := config.DefaultPath()
+func trendSummaries(trends []stats.InflationTrend) []format.InflationSummary {
+	var summaries []format.InflationSummary
+	for _, t := range trends {
+		summaries = append(summaries, format.InflationSummary{
+			ItemName:    t.Key, // Shows "Restaurant - Item"
+			Orders:      t.Count,
+			FirstSeen:   t.FirstSeen.Format("2006-01-02"),
+			FirstPrice:  t.FirstPrice,
+			LastPrice:   t.LastPrice,
+			Currency:    t.Currency,
+			TotalChange: t.TotalChange,
+			Confidence:  t.Confidence,
+		})
+	}
+	return summaries
+}
+
+// parsePercent reads a percentage such as "10%", "-5%" or "10".
+func parsePercent(value string) (float64, error) {
+	v := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	pct, err := strconv.ParseFloat(v, 64)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("invalid percentage %q", value)
 	}
-	cfg, err := config.Load(path)
+	return pct, nil
+}
+
+// loadCPI reads the reference CPI series. Without --cpi, a missing
+// cpi.csv in the config dir just means no comparison.
+func loadCPI(path string) (stats.CPI, error) {
+	if path != "" {
+		return stats.LoadCPI(path)
+	}
+	dir, err := config.BaseDir()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("not logged in; run 'zocli auth' first")
+		return nil, err
+	}
+	cpi, err := stats.LoadCPI(filepath.Join(dir, "cpi.csv"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return cpi, err
+}
+
+func runInflationIndex(orders []zomato.Order, group string, cpi stats.CPI) error {
+	var all [][]stats.IndexPoint
+	for _, cur := range stats.SplitByCurrency(orders) {
+		points, err := stats.PersonalInflationIndex(cur.Orders, group)
+		if err != nil {
+			return err
 		}
-		return err
+		if cpi != nil {
+			stats.CompareCPI(points, cpi, group)
+		}
+		all = append(all, points)
 	}
-	client := zomato.NewClient(cfg.Cookie)
-	raw, err := client.DebugFetchFirstPageRaw(context.Background())
-	if err != nil {
-		return err
+
+	if outputFormat != format.OutputTable {
+		var points []stats.IndexPoint
+		for _, p := range all {
+			points = append(points, p...)
+		}
+		return format.WriteSections(os.Stdout, outputFormat, format.IndexRecords(points))
+	}
+	printed := false
+	for _, points := range all {
+		if len(points) == 0 {
+			continue
+		}
+		if printed {
+			fmt.Println()
+		}
+		printed = true
+		fmt.Printf("Personal food inflation (%s, %s = 100)\n", points[0].Currency, points[0].Label)
+		format.InflationIndexTable(os.Stdout, points, cpi != nil)
+		if personal, cpiChange, hasCPI, ok := stats.AnnualChange(points); ok {
+			line := fmt.Sprintf("\nOver the last year: %+.1f%%", personal)
+			if hasCPI {
+				line += fmt.Sprintf(" (CPI %+.1f%%)", cpiChange)
+			}
+			fmt.Println(line)
+		}
+	}
+	if !printed {
+		fmt.Println("Not enough priced items for an index yet.")
 	}
-	fmt.Println(raw)
 	return nil
 }
 
 func runDash(args []string) error {
-	storePath, err := store.DefaultPath()
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintDashUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("dash", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintDashUsage(os.Stderr)
+	}
+	currency := addCurrencyFlags(fs)
+	cpiFile := fs.String("cpi", "", "Reference CPI CSV for the inflation chart (default: cpi.csv in the zocli config dir)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintDashUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+
+	orders, err := loadOrders()
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
+	orders, err = normalizePriceNames(orders)
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	orders, err = currency.apply(orders)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
 
-	m := tui.NewModel(orders)
+	loc, err := reportLocation(account)
+	if err != nil {
+		return err
+	}
+	_, cfg, err := loadAccountConfig(account)
+	if err != nil {
+		return err
+	}
+	cpi, err := loadCPI(*cpiFile)
+	if err != nil {
+		return err
+	}
+	m := tui.NewModel(orders, loc, cfg.Budgets, cpi)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("dashboard error: %w", err)
@@ -673,7 +1405,10 @@ func must(err error) {
 		os.Exit(0)
 	}
 	fmt.Fprintln(os.Stderr, "Error:", err)
-	os.Exit(1)
+	if hint := remediation(err); hint != "" {
+		fmt.Fprintln(os.Stderr, hint)
+	}
+	os.Exit(exitCode(err))
 }
 
 func runExport(args []string) error {
@@ -681,26 +1416,21 @@ func runExport(args []string) error {
 	fs.SetOutput(os.Stderr)
 	format := fs.String("format", "csv", "Output format: csv, json")
 	output := fs.String("output", "", "Output file (default: stdout)")
+	filters := filter.Register(fs, true)
 	
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	
-	storePath, err := store.DefaultPath()
-	if err != nil {
-		return err
-	}
-	st, err := store.New(storePath)
+	opts, err := filterOptions(filters, "")
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	
+	orders, err := queryOrders(storeQuery(opts, true))
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
+	orders = opts.Apply(orders)
 	
 	var w io.Writer = os.Stdout
 	if *output != "" {
@@ -723,31 +1453,92 @@ func runExport(args []string) error {
 }
 
 func runSuggest(args []string) error {
-	storePath, err := store.DefaultPath()
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintSuggestUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("suggest", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintSuggestUsage(os.Stderr)
+	}
+	top := fs.Int("top", 3, "Number of suggestions")
+	excludeRecent := fs.String("exclude-recent", "", "Skip restaurants ordered from since then (3d, 2w, yesterday)")
+	budgetFlag := fs.String("budget", "", "Only restaurants whose average order costs at most this, e.g. 400")
+	newPlaces := fs.Bool("new", false, "Suggest places ordered from only once or twice")
+	seed := fs.Int64("seed", 0, "Random seed, for a reproducible pick")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintSuggestUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+	if *top < 1 {
+		return fmt.Errorf("--top must be at least 1, got %d", *top)
+	}
+
+	loc, err := reportLocation(account)
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
+	opts := stats.RecommendOptions{Now: time.Now().In(loc), Count: *top, New: *newPlaces}
+	if *excludeRecent != "" {
+		start, _, err := filter.ParseDate(*excludeRecent, opts.Now)
+		if err != nil {
+			return fmt.Errorf("invalid --exclude-recent: %w", err)
+		}
+		opts.ExcludeSince = start
+	}
+	if strings.TrimSpace(*budgetFlag) != "" {
+		opts.Budget, err = zomato.ParseMoney(*budgetFlag, "")
+		if err != nil {
+			return fmt.Errorf("invalid --budget %q: %w", *budgetFlag, err)
+		}
+		if opts.Budget.Amount <= 0 {
+			return fmt.Errorf("--budget must be positive, got %s", *budgetFlag)
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			opts.Rand = rand.New(rand.NewSource(*seed))
+		}
+	})
+
+	orders, err := loadOrders()
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	orders, err = normalizeNames(orders)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
-	
-	restaurant, dish, err := stats.SuggestRestaurant(orders)
+
+	recs, err := stats.Recommend(orders, opts)
+	if outputFormat != format.OutputTable {
+		if err != nil {
+			return err
+		}
+		return format.WriteSections(os.Stdout, outputFormat, format.SuggestionRecords(recs))
+	}
 	if err != nil {
-		fmt.Printf("🎲  Suggest failed: %v\n\n(Tip: Run 'zocli sync' to fetch orders first)\n", err)
+		fmt.Printf("🎲  Suggest failed: %v\n", err)
+		if len(orders) == 0 {
+			fmt.Printf("\n(Tip: Run 'zocli sync' to fetch orders first)\n")
+		}
 		return nil
 	}
-	
-	fmt.Printf("🎲 How about ordering from: \n\n  ✨ %s ✨\n", restaurant)
-	if dish != "" {
-		fmt.Printf("  (Maybe try the %s?)\n", dish)
+
+	fmt.Printf("🎲 How about ordering from: \n\n")
+	for i, rec := range recs {
+		fmt.Printf("  %d. ✨ %s ✨\n", i+1, rec.Restaurant)
+		if rec.Dish != "" {
+			fmt.Printf("     (Maybe try the %s?)\n", rec.Dish)
+		}
+		fmt.Printf("     %s\n", strings.Join(rec.Reasons, " · "))
+		if i < len(recs)-1 {
+			fmt.Println()
+		}
 	}
 	fmt.Println()
 	return nil
@@ -757,26 +1548,30 @@ func runWrapped(args []string) error {
 	fs := flag.NewFlagSet("wrapped", flag.ContinueOnError)
 	fs.SetOutput(os.Stderr)
 	yearFlag := fs.Int("year", 0, "Year to generate wrapped for (default: latest year in data)")
+	currency := addCurrencyFlags(fs)
+	filters := filter.Register(fs, false)
 	
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	opts, err := filterOptions(filters, currency.code())
+	if err != nil {
+		return err
+	}
 
-	storePath, err := store.DefaultPath()
+	orders, err := queryOrders(storeQuery(opts, false))
 	if err != nil {
 		return err
 	}
-	st, err := store.New(storePath)
+	orders, err = normalizeNames(orders)
 	if err != nil {
 		return err
 	}
-	orders, err := st.Load()
+	orders, err = currency.apply(orders)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.New("no stored orders yet; run 'zocli sync' first")
-		}
 		return err
 	}
+	orders = opts.Apply(orders)
 	
 	summary := stats.ComputeSummary(orders)
 	targetYear := *yearFlag
@@ -790,9 +1585,13 @@ func runWrapped(args []string) error {
 	}
 	
 	// Filter for the target year
-	start := time.Date(targetYear, 1, 1, 0, 0, 0, 0, time.Local)
-	end := start.AddDate(1, 0, 0) // Start of next year
-	yearOrders := stats.FilterOrdersByDate(orders, start, end)
+	loc, err := reportLocation(account)
+	if err != nil {
+		return err
+	}
+	start := time.Date(targetYear, 1, 1, 0, 0, 0, 0, loc)
+	year := filter.Options{Since: start, Until: start.AddDate(1, 0, 0)}
+	yearOrders := year.Apply(orders)
 	
 	if len(yearOrders) == 0 {
 		fmt.Printf("No orders found for %d. Try a different year.\n", targetYear)
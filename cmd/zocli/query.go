@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/format"
+	"github.com/maheshrijal/zocli/internal/query"
+)
+
+func runQuery(args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintQueryUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintQueryUsage(os.Stderr)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	text := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if text == "" {
+		cli.PrintQueryUsage(os.Stderr)
+		return errors.New("missing query")
+	}
+
+	q, err := query.Parse(text)
+	if err != nil {
+		return err
+	}
+	orders, err := loadOrders()
+	if err != nil {
+		return err
+	}
+	res, err := q.Run(orders)
+	if err != nil {
+		return err
+	}
+	if outputFormat != format.OutputTable {
+		return format.WriteSections(os.Stdout, outputFormat, format.QueryRecords(res))
+	}
+	format.QueryTable(os.Stdout, res)
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/archive"
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/format"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// archiveRecorder returns a Client.OnRaw hook that archives every response
+// next to the store. Archive failures never fail a sync; the first one is
+// reported on stderr.
+func archiveRecorder(storePath string) func(zomato.RawResponse) {
+	arc := archive.New(archive.DirFor(storePath))
+	warned := false
+	return func(raw zomato.RawResponse) {
+		if err := arc.Write(raw.Kind, raw.Key, raw.FetchedAt, raw.Body); err != nil && !warned {
+			warned = true
+			fmt.Fprintf(os.Stderr, "\nWarning: could not archive raw response: %v\n", err)
+		}
+	}
+}
+
+func runReparse(args []string) error {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		cli.PrintReparseUsage(os.Stdout)
+		return nil
+	}
+	fs := flag.NewFlagSet("reparse", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		cli.PrintReparseUsage(os.Stderr)
+	}
+	list := fs.Bool("list", false, "List archived responses instead of reparsing")
+	show := fs.String("show", "", "Print the decompressed body of one archived file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		cli.PrintReparseUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(extra, " "))
+	}
+
+	st, err := openStore()
+	if err != nil {
+		return err
+	}
+	arc := archive.New(archive.DirFor(st.Path()))
+	entries, err := arc.Entries()
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		format.ArchiveTable(os.Stdout, entries)
+		return nil
+	}
+	if *show != "" {
+		for _, entry := range entries {
+			if filepath.Base(entry.Path) == filepath.Base(*show) {
+				body, err := arc.Read(entry)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(body))
+				return nil
+			}
+		}
+		return fmt.Errorf("no archived response named %s; see 'zocli reparse --list'", *show)
+	}
+
+	if len(entries) == 0 {
+		return errors.New("no archived responses yet; run 'zocli sync' first")
+	}
+
+	// Reparsing writes the store, so stored times move to the account's
+	// zone first.
+	if st, err = openStoreForWrite(); err != nil {
+		return err
+	}
+	existing, err := st.Load()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	loc, err := accountLocation(account)
+	if err != nil {
+		return err
+	}
+
+	// Entries are oldest first, so later responses win for the same order.
+	byID := map[string]zomato.Order{}
+	var ids []string
+	var pages, detailCount, failed int
+	type detail struct {
+		body      []byte
+		fetchedAt time.Time
+	}
+	details := map[string]detail{} // by archive.Key of the hash ID
+	for _, entry := range entries {
+		body, err := arc.Read(entry)
+		if err == nil {
+			switch entry.Kind {
+			case zomato.RawOrders:
+				var orders []zomato.Order
+				if orders, err = zomato.ParseOrdersPage(body, loc); err == nil {
+					for _, order := range orders {
+						if _, ok := byID[order.ID]; !ok {
+							ids = append(ids, order.ID)
+						}
+						byID[order.ID] = order
+					}
+					pages++
+				}
+			case zomato.RawDetails:
+				// Parsed again once the order, and so its currency, is known.
+				if _, _, err = zomato.ParseOrderDetails(body, zomato.DefaultCurrency); err == nil {
+					details[entry.Key] = detail{body: body, fetchedAt: entry.FetchedAt}
+					detailCount++
+				}
+			default:
+				continue
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", filepath.Base(entry.Path), err)
+		}
+	}
+
+	// Details may belong to orders whose pages predate the archive.
+	for _, order := range existing {
+		if _, ok := byID[order.ID]; !ok && order.HashID != "" {
+			if _, ok := details[archive.Key(order.HashID)]; ok {
+				byID[order.ID] = order
+				ids = append(ids, order.ID)
+			}
+		}
+	}
+
+	orders := make([]zomato.Order, 0, len(ids))
+	for _, id := range ids {
+		order := byID[id]
+		if d, ok := details[archive.Key(order.HashID)]; ok && order.HashID != "" {
+			parsed, items, err := zomato.ParseOrderDetails(d.body, order.Total.Currency)
+			if err != nil {
+				return err
+			}
+			parsed.FetchedAt = d.fetchedAt
+			order.Details = &parsed
+			if len(items) > 0 {
+				order.Items = items
+			}
+		}
+		orders = append(orders, order)
+	}
+
+	fmt.Printf("Reparsed %d pages and %d detail responses", pages, detailCount)
+	if failed > 0 {
+		fmt.Printf(" (%d skipped)", failed)
+	}
+	fmt.Println()
+
+	result, err := st.Merge(orders)
+	if err != nil {
+		return err
+	}
+	printMergeResult(result, st.Path())
+	return nil
+}
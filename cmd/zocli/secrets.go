@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/config"
+	"golang.org/x/term"
+)
+
+// passphrase caches the encrypted-file passphrase for the rest of the run.
+var passphrase string
+
+func secretOptions() config.SecretOptions {
+	return config.SecretOptions{
+		Account:    account,
+		Passphrase: readPassphrase,
+	}
+}
+
+// readPassphrase takes the passphrase from ZOCLI_PASSPHRASE or prompts for
+// it when stdin is a terminal.
+func readPassphrase() (string, error) {
+	if passphrase != "" {
+		return passphrase, nil
+	}
+	if env := os.Getenv("ZOCLI_PASSPHRASE"); env != "" {
+		passphrase = env
+		return passphrase, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("passphrase required for the encrypted cookie file; set ZOCLI_PASSPHRASE")
+	}
+	fmt.Fprint(os.Stderr, "Passphrase for saved cookie: ")
+	data, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	passphrase = string(data)
+	return passphrase, nil
+}
+
+// loadCookie returns the selected account's cookie, first moving a
+// plaintext cookie left in config.json into the secret store.
+func loadCookie() (string, error) {
+	cfgPath, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	migrated, err := config.MigrateCookie(cfgPath, secretOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cookie is still stored in plaintext in %s: %v\n", cfgPath, err)
+	} else if migrated {
+		fmt.Fprintf(os.Stderr, "Moved saved cookie from %s to %s.\n", cfgPath, config.SecretLocation(cfgPath))
+	}
+	return config.LoadCookie(cfgPath, secretOptions())
+}
+
+func runAuthSecrets(args []string) error {
+	if len(args) > 1 {
+		cli.PrintAuthSecretsUsage(os.Stderr)
+		return fmt.Errorf("unknown arguments: %s", strings.Join(args[1:], " "))
+	}
+	cfgPath, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(args) == 0 {
+		fmt.Printf("Secrets backend: %s (%s)\n", cfg.SecretBackend(), config.SecretLocation(cfgPath))
+		return nil
+	}
+
+	backend := strings.ToLower(strings.TrimSpace(args[0]))
+	switch backend {
+	case "help", "-h", "--help":
+		cli.PrintAuthSecretsUsage(os.Stdout)
+		return nil
+	case config.SecretsKeyring, config.SecretsFile, config.SecretsPlaintext:
+	default:
+		cli.PrintAuthSecretsUsage(os.Stderr)
+		return fmt.Errorf("unknown secrets backend: %s", backend)
+	}
+	if backend == cfg.SecretBackend() {
+		fmt.Printf("Already using the %s backend.\n", backend)
+		return nil
+	}
+	if err := config.SwitchSecrets(cfgPath, backend, secretOptions()); err != nil {
+		return err
+	}
+	fmt.Printf("Cookie is now stored in %s\n", config.SecretLocation(cfgPath))
+	return nil
+}
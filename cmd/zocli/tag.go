@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/maheshrijal/zocli/internal/cli"
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/tags"
+)
+
+// tagRulesPath is the user's tag rules file, shared by all accounts.
+func tagRulesPath() (string, error) {
+	dir, err := config.BaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tag_rules.json"), nil
+}
+
+func loadTagRules() (tags.Rules, error) {
+	path, err := tagRulesPath()
+	if err != nil {
+		return nil, err
+	}
+	user, err := tags.LoadRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return tags.Merge(tags.DefaultRules(), user), nil
+}
+
+// newTagger builds a tagger from the tag rules and the manual tag edits of
+// the given accounts.
+func newTagger(accounts ...string) (*tags.Tagger, error) {
+	rules, err := loadTagRules()
+	if err != nil {
+		return nil, err
+	}
+	manual := map[string]tags.Manual{}
+	for _, name := range accounts {
+		_, cfg, err := loadAccountConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		for id, m := range cfg.OrderTags {
+			manual[id] = m
+		}
+	}
+	return tags.NewTagger(rules, manual), nil
+}
+
+// runTag parses its own arguments: "-tag" changes would look like flags.
+func runTag(args []string) error {
+	if len(args) == 0 {
+		cli.PrintTagUsage(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "help", "-h", "--help":
+		cli.PrintTagUsage(os.Stdout)
+		return nil
+	case "rules":
+		if len(args) > 1 {
+			cli.PrintTagUsage(os.Stderr)
+			return fmt.Errorf("unknown arguments: %s", strings.Join(args[1:], " "))
+		}
+		return runTagRules()
+	}
+	return runTagOrder(args[0], args[1:])
+}
+
+func runTagOrder(id string, changes []string) error {
+	orders, err := loadOrders()
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, order := range orders {
+		if order.ID == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("no stored order with ID %q (see 'zocli orders')", id)
+	}
+
+	if len(changes) > 0 {
+		cfgPath, cfg, err := loadAccountConfig(account)
+		if err != nil {
+			return err
+		}
+		edit, err := tags.Edit(cfg.OrderTags[id], changes)
+		if err != nil {
+			return err
+		}
+		if err := config.Update(cfgPath, func(c *config.Config) {
+			if len(edit.Add) == 0 && len(edit.Remove) == 0 {
+				delete(c.OrderTags, id)
+				return
+			}
+			if c.OrderTags == nil {
+				c.OrderTags = map[string]tags.Manual{}
+			}
+			c.OrderTags[id] = edit
+		}); err != nil {
+			return err
+		}
+	}
+
+	tagger, err := newTagger(account)
+	if err != nil {
+		return err
+	}
+	order := orders[index]
+	orderTags := tagger.Tags(order)
+	fmt.Printf("%s  %s  %s\n", order.ID, order.Restaurant, order.PlacedAt.Format("2006-01-02"))
+	if len(orderTags) == 0 {
+		fmt.Println("Tags: none")
+		return nil
+	}
+	fmt.Printf("Tags: %s\n", strings.Join(orderTags, ", "))
+	return nil
+}
+
+func runTagRules() error {
+	rules, err := loadTagRules()
+	if err != nil {
+		return err
+	}
+	path, err := tagRulesPath()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(rules))
+	for tag := range rules {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tRESTAURANT KEYWORDS\tDISH KEYWORDS")
+	fmt.Fprintln(tw, "---\t-------------------\t-------------")
+	for _, tag := range names {
+		rule := rules[tag]
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", tag, keywordList(rule.Restaurants), keywordList(rule.Items))
+	}
+	tw.Flush()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("\nAdd your own rules in %s (see 'zocli help tag').\n", path)
+	} else {
+		fmt.Printf("\nUser rules: %s\n", path)
+	}
+	return nil
+}
+
+// keywordList shortens long keyword lists for display.
+func keywordList(keywords []string) string {
+	const shown = 4
+	if len(keywords) == 0 {
+		return "-"
+	}
+	if len(keywords) <= shown {
+		return strings.Join(keywords, ", ")
+	}
+	return fmt.Sprintf("%s, +%d more", strings.Join(keywords[:shown], ", "), len(keywords)-shown)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/config"
+	"github.com/maheshrijal/zocli/internal/store"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// reportTimezone is set by the global --tz flag. It changes how order times
+// are shown in reports, never how they are stored.
+var reportTimezone *time.Location
+
+// loadAccountConfig returns an account's config path and contents. A missing
+// config file yields the zero Config.
+func loadAccountConfig(name string) (string, config.Config, error) {
+	cfgPath, err := config.PathFor(name)
+	if err != nil {
+		return "", config.Config{}, err
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", config.Config{}, fmt.Errorf("load config: %w", err)
+	}
+	return cfgPath, cfg, nil
+}
+
+// accountLocation is the region timezone an account's order dates are
+// interpreted in.
+func accountLocation(name string) (*time.Location, error) {
+	_, cfg, err := loadAccountConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	return zomato.LoadLocation(cfg.Timezone)
+}
+
+// reportLocation is the zone reports show an account's orders in: --tz when
+// given, otherwise the account's region timezone.
+func reportLocation(name string) (*time.Location, error) {
+	if reportTimezone != nil {
+		return reportTimezone, nil
+	}
+	return accountLocation(name)
+}
+
+// migrateTimezone reinterprets stored order times in the account's region
+// timezone when they were written in another zone, e.g. by versions that
+// parsed dates in the machine's local zone. It runs once per zone change,
+// from commands that write the store.
+func migrateTimezone(st store.Store, cfgPath string, cfg config.Config) error {
+	loc, err := zomato.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return err
+	}
+	if cfg.OrdersTimezone == loc.String() {
+		return nil
+	}
+	orders, err := st.Load()
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing stored yet; the first sync parses in the right zone.
+			return nil
+		}
+		return err
+	}
+	if err := st.Save(zomato.Rezone(orders, loc)); err != nil {
+		return fmt.Errorf("migrate order times to %s: %w", loc, err)
+	}
+	if err := recordOrdersTimezone(cfgPath, loc); err != nil {
+		return err
+	}
+	if len(orders) > 0 {
+		fmt.Fprintf(os.Stderr, "Updated %d stored order times to %s\n", len(orders), loc)
+	}
+	return nil
+}
+
+// recordOrdersTimezone notes that the store at cfgPath's account holds order
+// times parsed in loc, so migrateTimezone leaves them alone.
+func recordOrdersTimezone(cfgPath string, loc *time.Location) error {
+	cfg, err := config.Load(cfgPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if cfg.OrdersTimezone == loc.String() {
+		return nil
+	}
+	return config.Update(cfgPath, func(c *config.Config) {
+		c.OrdersTimezone = loc.String()
+	})
+}
+
+// loadOrders loads the selected account's orders for a report, with times
+// shown in the report timezone.
+func loadOrders() ([]zomato.Order, error) {
+	return queryOrders(store.Query{})
+}
+
+// queryOrders is loadOrders for the stored orders matching q.
+func queryOrders(q store.Query) ([]zomato.Order, error) {
+	st, err := openStore()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := st.Query(q)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("no stored orders yet; run 'zocli sync' first")
+		}
+		return nil, err
+	}
+	loc, err := reportLocation(account)
+	if err != nil {
+		return nil, err
+	}
+	return zomato.InLocation(orders, loc), nil
+}
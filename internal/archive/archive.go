@@ -0,0 +1,153 @@
+// Package archive keeps the raw Zomato API responses fetched by sync so
+// orders can be re-parsed with a newer normalizer without re-fetching.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+)
+
+const (
+	timeLayout = "20060102T150405.000000000Z"
+	extension  = ".json.gz"
+)
+
+// Entry describes one archived response. Key is the page number for order
+// pages and the order hash ID for details, as mapped by Key.
+type Entry struct {
+	Path      string
+	Kind      string
+	Key       string
+	FetchedAt time.Time
+	Size      int64
+}
+
+// Page returns the page number of an orders entry, or 0.
+func (e Entry) Page() int {
+	page, _ := strconv.Atoi(e.Key)
+	return page
+}
+
+// Archive is a directory of gzip-compressed responses named
+// <timestamp>-<kind>-<key>.json.gz.
+type Archive struct {
+	Dir string
+}
+
+// DirFor returns the archive directory kept next to a store file.
+func DirFor(storePath string) string {
+	return filepath.Join(filepath.Dir(storePath), "raw")
+}
+
+func New(dir string) *Archive {
+	return &Archive{Dir: dir}
+}
+
+// Write stores body compressed under a name derived from kind, key and at.
+func (a *Archive) Write(kind, key string, at time.Time, body []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.ModTime = at
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s-%s%s", at.UTC().Format(timeLayout), kind, Key(key), extension)
+	return fileutil.WriteAtomic(filepath.Join(a.Dir, name), buf.Bytes(), 0o600, 0)
+}
+
+// Entries lists archived responses oldest first. A missing directory yields
+// no entries.
+func (a *Archive) Entries() ([]Entry, error) {
+	files, err := os.ReadDir(a.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		entry, ok := parseName(file.Name())
+		if !ok {
+			continue
+		}
+		entry.Path = filepath.Join(a.Dir, file.Name())
+		if info, err := file.Info(); err == nil {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].FetchedAt.Equal(entries[j].FetchedAt) {
+			return entries[i].FetchedAt.Before(entries[j].FetchedAt)
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// Read returns the decompressed body of an entry.
+func (a *Archive) Read(entry Entry) ([]byte, error) {
+	f, err := os.Open(entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(entry.Path), err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+func parseName(name string) (Entry, bool) {
+	var entry Entry
+	base, ok := strings.CutSuffix(name, extension)
+	if !ok {
+		return entry, false
+	}
+	parts := strings.SplitN(base, "-", 3)
+	if len(parts) != 3 {
+		return entry, false
+	}
+	at, err := time.Parse(timeLayout, parts[0])
+	if err != nil {
+		return entry, false
+	}
+	entry.FetchedAt = at
+	entry.Kind = parts[1]
+	entry.Key = parts[2]
+	return entry, true
+}
+
+// Key maps a page number or hash ID to the form kept in file names and
+// Entry.Key: characters other than ASCII letters, digits and underscores
+// become underscores.
+func Key(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
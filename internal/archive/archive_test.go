@@ -0,0 +1,79 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive_WriteEntriesRead(t *testing.T) {
+	a := New(filepath.Join(t.TempDir(), "raw"))
+
+	entries, err := a.Entries()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Entries on missing dir = %v, %v; want none", entries, err)
+	}
+
+	t1 := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	if err := a.Write("orders", "2", t2, []byte(`{"page":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Write("orders", "1", t1, []byte(`{"page":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Write("details", "ab/c-d", t2, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	// Unrelated files are ignored.
+	if err := os.WriteFile(filepath.Join(a.Dir, "notes.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = a.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Got %d entries, want 3", len(entries))
+	}
+	if entries[0].Page() != 1 || !entries[0].FetchedAt.Equal(t1) {
+		t.Errorf("entries[0] = %+v, want page 1 fetched at %v", entries[0], t1)
+	}
+	if entries[1].Kind != "details" || entries[1].Key != "ab_c_d" || entries[1].Key != Key("ab/c-d") {
+		t.Errorf("entries[1] = %+v, want details with sanitized key", entries[1])
+	}
+
+	body, err := a.Read(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"page":1}` {
+		t.Errorf("Read = %s, want page 1 body", body)
+	}
+}
+
+func TestKeyMatchesEntries(t *testing.T) {
+	a := New(filepath.Join(t.TempDir(), "raw"))
+	hashes := []string{"abc-123", "x.y_z", "plain9"}
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for _, hash := range hashes {
+		if err := a.Write("details", hash, at, []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := a.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]bool{}
+	for _, e := range entries {
+		keys[e.Key] = true
+	}
+	for _, hash := range hashes {
+		if !keys[Key(hash)] {
+			t.Errorf("no entry with Key(%q) = %q in %v", hash, Key(hash), keys)
+		}
+	}
+}
@@ -26,6 +26,7 @@ type LoginOptions struct {
 	UserDataDir string
 	ProfileDir  string
 	SkipWait    bool
+	Secrets     config.SecretOptions
 }
 
 func LoginAndSaveCookie(ctx context.Context, cfgPath string, headless bool) error {
@@ -202,11 +203,11 @@ func captureCookie(ctx context.Context, cfgPath string, opts LoginOptions) error
 		return errors.New("no zomato cookies found; make sure you logged in in the opened browser")
 	}
 
-	if err := config.Save(cfgPath, config.Config{Cookie: cookieHeader}); err != nil {
+	if err := config.SaveCookie(cfgPath, cookieHeader, opts.Secrets); err != nil {
 		return err
 	}
 
-	fmt.Printf("Saved cookie to %s\n", cfgPath)
+	fmt.Printf("Saved cookie to %s\n", config.SecretLocation(cfgPath))
 	return nil
 }
 
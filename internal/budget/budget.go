@@ -0,0 +1,184 @@
+// Package budget tracks monthly spending limits against stored orders.
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/filter"
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Budget is a monthly spending limit, optionally narrowed to restaurants
+// matching Restaurant and to orders in Category. Only orders in the
+// limit's currency count towards it.
+type Budget struct {
+	Monthly    zomato.Money `json:"monthly"`
+	Restaurant string       `json:"restaurant,omitempty"`
+	Category   string       `json:"category,omitempty"`
+}
+
+// Categories are the time-of-day windows from stats plus weekday and
+// weekend.
+func Categories() []string {
+	out := make([]string, 0, len(stats.TimeWindows)+2)
+	for _, w := range stats.TimeWindows {
+		out = append(out, w.Slug)
+	}
+	return append(out, "weekday", "weekend")
+}
+
+// ParseCategory validates a category name.
+func ParseCategory(value string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	for _, c := range Categories() {
+		if v == c {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown category %q (use %s)", value, strings.Join(Categories(), ", "))
+}
+
+func inCategory(category string, t time.Time) bool {
+	switch category {
+	case "":
+		return true
+	case "weekend":
+		return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+	case "weekday":
+		return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	}
+	for _, w := range stats.TimeWindows {
+		if w.Slug == category {
+			return w.Contains(t)
+		}
+	}
+	return false
+}
+
+// Name describes what the budget covers, e.g. "pizza, late-night".
+func (b Budget) Name() string {
+	var parts []string
+	if b.Restaurant != "" {
+		parts = append(parts, b.Restaurant)
+	}
+	if b.Category != "" {
+		parts = append(parts, b.Category)
+	}
+	if len(parts) == 0 {
+		return "all orders"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SameScope reports whether b and other cover the same orders, so setting
+// one replaces the other.
+func (b Budget) SameScope(other Budget) bool {
+	return strings.EqualFold(b.Restaurant, other.Restaurant) && b.Category == other.Category
+}
+
+// Set adds b to budgets, replacing a budget with the same scope.
+func Set(budgets []Budget, b Budget) []Budget {
+	for i := range budgets {
+		if budgets[i].SameScope(b) {
+			budgets[i] = b
+			return budgets
+		}
+	}
+	return append(budgets, b)
+}
+
+// Remove drops the budget with b's scope, reporting whether there was one.
+func Remove(budgets []Budget, b Budget) ([]Budget, bool) {
+	for i := range budgets {
+		if budgets[i].SameScope(b) {
+			return append(budgets[:i:i], budgets[i+1:]...), true
+		}
+	}
+	return budgets, false
+}
+
+// Status is a budget's progress through the month containing Now.
+type Status struct {
+	Budget
+	Now       time.Time
+	Orders    int
+	Spent     zomato.Money
+	Projected zomato.Money // month-end spend at the current daily run rate
+}
+
+// Percent is the share of the limit spent so far.
+func (s Status) Percent() float64 {
+	if s.Monthly.Amount <= 0 {
+		return 0
+	}
+	return float64(s.Spent.Amount) / float64(s.Monthly.Amount) * 100
+}
+
+// Over reports whether spending has exceeded the limit.
+func (s Status) Over() bool {
+	return s.Spent.Amount > s.Monthly.Amount
+}
+
+// OnTrack reports whether the projected month-end spend stays within the
+// limit.
+func (s Status) OnTrack() bool {
+	return s.Projected.Amount <= s.Monthly.Amount
+}
+
+// Evaluate measures b against the orders placed in now's month, in now's
+// location.
+func Evaluate(b Budget, orders []zomato.Order, now time.Time) (Status, error) {
+	var restaurant filter.Matcher
+	if b.Restaurant != "" {
+		m, err := filter.NewMatcher(b.Restaurant)
+		if err != nil {
+			return Status{}, err
+		}
+		restaurant = m
+	}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 1, 0)
+
+	st := Status{Budget: b, Now: now, Spent: zomato.Money{Currency: b.Monthly.Currency}}
+	for _, order := range orders {
+		placed := order.PlacedAt.In(now.Location())
+		if placed.Before(start) || !placed.Before(end) {
+			continue
+		}
+		if order.Total.Currency != b.Monthly.Currency {
+			continue
+		}
+		if !restaurant.IsZero() && !restaurant.Match(order.Restaurant) {
+			continue
+		}
+		if !inCategory(b.Category, placed) {
+			continue
+		}
+		st.Orders++
+		st.Spent = st.Spent.Add(order.Total)
+	}
+
+	// Days elapsed include today, so the run rate doesn't spike on the
+	// morning of the 1st.
+	days := end.AddDate(0, 0, -1).Day()
+	st.Projected = st.Spent.Mul(days).Div(now.Day())
+	return st, nil
+}
+
+// EvaluateAll evaluates every budget, most used first.
+func EvaluateAll(budgets []Budget, orders []zomato.Order, now time.Time) ([]Status, error) {
+	out := make([]Status, 0, len(budgets))
+	for _, b := range budgets {
+		st, err := Evaluate(b, orders, now)
+		if err != nil {
+			return nil, fmt.Errorf("budget %s: %w", b.Name(), err)
+		}
+		out = append(out, st)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Percent() > out[j].Percent() })
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func inr(major int64) zomato.Money { return zomato.Money{Amount: major * 100, Currency: "INR"} }
+
+func TestEvaluate(t *testing.T) {
+	// Friday 10 January 2025, evening.
+	now := time.Date(2025, 1, 10, 21, 0, 0, 0, time.UTC)
+	at := func(m time.Month, d, h int) time.Time { return time.Date(2025, m, d, h, 0, 0, 0, time.UTC) }
+	orders := []zomato.Order{
+		{Restaurant: "Pizza Hut", PlacedAt: at(1, 2, 1), Total: inr(600)},      // Thursday, late night
+		{Restaurant: "Biryani Blues", PlacedAt: at(1, 4, 20), Total: inr(900)}, // Saturday evening
+		{Restaurant: "La Pino'z Pizza", PlacedAt: at(1, 8, 13), Total: inr(500)},
+		{Restaurant: "Pizza Hut", PlacedAt: at(12, 31, 23).AddDate(-1, 0, 0), Total: inr(700)}, // last month
+		{Restaurant: "Dubai Grill", PlacedAt: at(1, 5, 20), Total: zomato.Money{Amount: 4000, Currency: "AED"}},
+	}
+
+	tests := []struct {
+		budget    Budget
+		orders    int
+		spent     zomato.Money
+		projected zomato.Money
+		over      bool
+		onTrack   bool
+	}{
+		{Budget{Monthly: inr(8000)}, 3, inr(2000), inr(6200), false, true},
+		{Budget{Monthly: inr(1000), Restaurant: "pizza"}, 2, inr(1100), inr(3410), true, false},
+		{Budget{Monthly: inr(500), Category: "late-night"}, 1, inr(600), inr(1860), true, false},
+		{Budget{Monthly: inr(1000), Category: "weekend"}, 1, inr(900), inr(2790), false, false},
+		{Budget{Monthly: zomato.Money{Amount: 50000, Currency: "AED"}}, 1,
+			zomato.Money{Amount: 4000, Currency: "AED"}, zomato.Money{Amount: 12400, Currency: "AED"}, false, true},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.budget, orders, now)
+		if err != nil {
+			t.Fatalf("Evaluate(%s) failed: %v", tt.budget.Name(), err)
+		}
+		if got.Orders != tt.orders || got.Spent != tt.spent || got.Projected != tt.projected {
+			t.Errorf("Evaluate(%s) = %d orders, spent %v, projected %v; want %d, %v, %v",
+				tt.budget.Name(), got.Orders, got.Spent, got.Projected, tt.orders, tt.spent, tt.projected)
+		}
+		if got.Over() != tt.over || got.OnTrack() != tt.onTrack {
+			t.Errorf("Evaluate(%s): Over = %v, OnTrack = %v; want %v, %v",
+				tt.budget.Name(), got.Over(), got.OnTrack(), tt.over, tt.onTrack)
+		}
+	}
+}
+
+func TestSetAndRemove(t *testing.T) {
+	var budgets []Budget
+	budgets = Set(budgets, Budget{Monthly: inr(8000)})
+	budgets = Set(budgets, Budget{Monthly: inr(1000), Restaurant: "Pizza"})
+	budgets = Set(budgets, Budget{Monthly: inr(1500), Restaurant: "pizza"})
+	if len(budgets) != 2 || budgets[1].Monthly != inr(1500) {
+		t.Fatalf("Set = %+v, want the pizza budget replaced", budgets)
+	}
+
+	budgets, ok := Remove(budgets, Budget{Category: "late-night"})
+	if ok || len(budgets) != 2 {
+		t.Errorf("Remove(late-night) = %v, %d budgets; want false, 2", ok, len(budgets))
+	}
+	budgets, ok = Remove(budgets, Budget{})
+	if !ok || len(budgets) != 1 || budgets[0].Restaurant != "pizza" {
+		t.Errorf("Remove(all orders) = %v, %+v; want only the pizza budget left", ok, budgets)
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	if got, err := ParseCategory(" Late-Night "); err != nil || got != "late-night" {
+		t.Errorf("ParseCategory(Late-Night) = %q, %v", got, err)
+	}
+	if _, err := ParseCategory("brunch"); err == nil {
+		t.Error("ParseCategory(brunch) succeeded, want error")
+	}
+}
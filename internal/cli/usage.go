@@ -9,22 +9,50 @@ func PrintUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli - unofficial Zomato order tracker
 
 Usage:
-  zocli <command> [options]
+  zocli [--account NAME] <command> [options]
 
 Commands:
   auth       Log in / import cookies
+  accounts   Manage named accounts
   sync       Fetch orders and store locally
   dash       Interactive dashboard (TUI)
   orders     List stored orders
   stats      Summarize spend
+  heatmap    Calendar of orders per day
   inflation  Track unit price history
+  query      Filter, group and aggregate orders with a query
+  budget     Set monthly spending limits and check progress
+  alias      Count restaurant and dish name variants as one
+  tag        Show and edit an order's tags
   config     Show config and data paths
+  store      Manage local data (restore backups, migrate backend)
+  reparse    Rebuild stored orders from archived API responses
   export     Export data to CSV/JSON
-  suggest    Pick a random restaurant/dish
-  wrapped    Yearly food journey slideshow [--year 2024]
+  suggest    Suggest restaurants and dishes from your history
+  wrapped    Yearly food journey slideshow [--year 2024] [--base CUR]
   version    Print version
   help       Help for a command
 
+Global options:
+  --account NAME  Use a named account (default: $ZOCLI_ACCOUNT, then
+                  the one set with 'zocli accounts default')
+  --tz ZONE       Show order times in ZONE in reports (default: the
+                  account's timezone, see 'zocli help config')
+  --output FMT    Report format for orders, stats, inflation, query, budget
+                  status, suggest and heatmap:
+                  table (default), json, ndjson, csv or tsv. Amounts are
+                  decimal numbers in major units with a currency column.
+                  (export keeps --output for its destination file.)
+
+Exit codes:
+  0  success
+  1  general error
+  3  Zomato session expired or unauthorized (run 'zocli auth login')
+  4  rate limited by Zomato after retries
+  5  blocked by a captcha or bot-check page
+  6  Zomato response format changed
+  7  over budget ('zocli budget status')
+
 Try:
   zocli help auth
   zocli auth login
@@ -39,6 +67,7 @@ Usage:
   zocli auth import [--browser chrome|chromium|brave|edge|helium|vivaldi] [--profile "Default"] [--user-data-dir PATH] [--browser-path PATH]
   zocli auth logout
   zocli auth status [--offline]
+  zocli auth secrets [keyring|file|plaintext]
   zocli auth --cookie "<cookie header>"
   zocli auth --cookie-file PATH
 
@@ -96,6 +125,23 @@ Usage:
 `)
 }
 
+func PrintAuthSecretsUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli auth secrets
+
+Usage:
+  zocli auth secrets [keyring|file|plaintext]
+
+Shows or changes where the session cookie is stored:
+  keyring    OS keyring / Secret Service (default)
+  file       Encrypted file next to config.json; the passphrase is read from
+             ZOCLI_PASSPHRASE or prompted for
+  plaintext  config.json, as older versions did (not recommended)
+
+Cookies found in plaintext in config.json are moved to the selected backend
+automatically.
+`)
+}
+
 func PrintAuthLogoutUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli auth logout
 
@@ -108,15 +154,235 @@ func PrintSyncUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli sync
 
 Usage:
-  zocli sync [--mock]
+  zocli sync [--mock] [--full] [--details] [--retries 4] [--page-delay 500ms]
+             [--no-archive]
+
+Options:
+  --mock        Store sample data in the separate "sample" account instead of
+                hitting Zomato; the current account is left untouched and
+                --accounts all leaves the sample account out
+  --full        Re-fetch the complete order history (default: stop at the
+                first page that only contains orders already stored)
+  --details     Also fetch item prices, taxes, fees and discounts for orders
+                that don't have them yet (one request per order, cached)
+  --retries     Retries per request on 429, 5xx and timeouts, with
+                exponential backoff that honors Retry-After up to 30s
+                (default: 4)
+  --page-delay  Pause between page requests (default: 500ms)
+  --no-archive  Don't keep the raw API responses (see 'zocli help reparse')
+
+If a page still fails after retrying, the orders fetched so far are saved
+and the next 'zocli sync' resumes from the failed page.
+`)
+}
+
+func PrintReparseUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli reparse
+
+Sync keeps every raw API response, gzip-compressed, in a raw/ directory next
+to the store. reparse runs them through the current parser and merges the
+result into the store, so parser fixes apply without fetching again.
+
+Usage:
+  zocli reparse
+  zocli reparse --list
+  zocli reparse --show FILE
+
+Options:
+  --list  List archived responses
+  --show  Print the decompressed body of one archived file
 `)
 }
 
+// filterOptions documents the flags registered by filter.Register.
+const filterOptions = `
+Filters:
+  --since DATE        Only orders on or after DATE
+  --until DATE        Only orders up to and including DATE
+  --restaurant TEXT   Restaurant name contains TEXT, or matches /regexp/
+  --item TEXT         Any item name contains TEXT, or matches /regexp/
+  --status STATUS     Order status, e.g. Delivered
+  --min AMOUNT        Order total at least AMOUNT
+  --max AMOUNT        Order total at most AMOUNT
+
+DATE is 2024-01-15, 2024-01 or 2024, a relative age like 30d, 2w, 6m or 1y,
+or one of today, yesterday, this-week, last-week, this-month, last-month,
+this-year, last-year. AMOUNT may name its currency ("AED 50"); otherwise
+it is in INR, or the --base currency. Orders in other currencies don't
+match --min or --max.
+`
+
+const sortOptions = `  --sort KEY          Sort by date (newest first), total (highest first,
+                      per currency) or restaurant (A-Z)
+  --reverse           Reverse the sort order
+`
+
 func PrintOrdersUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli orders
 
 Usage:
-  zocli orders [--limit 20]
+  zocli orders [--limit 20] [filters] [--sort date|total|restaurant] [--reverse]
+
+Options:
+  --limit N           Max orders to print (0 for all)
+`+sortOptions+filterOptions)
+}
+
+func PrintInflationUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli inflation
+
+Usage:
+  zocli inflation [--top 5] [--sort count|change|abs-change|price]
+                  [--min-points 2] [--min-change 10%] [filters] [item name]
+  zocli inflation --compare [options] [filters] ITEM
+  zocli inflation --index [--group month|year] [--cpi FILE] [filters]
+
+Without an item name, shows the price trend of the most ordered items.
+With one, shows the price history of matching items; with --compare, the
+trend of each restaurant's matching dish, cheapest first. Options go before
+the item name.
+
+Options:
+  --top         Number of trends to show (0 for all)
+  --sort        count (most priced orders), change (biggest rise),
+                abs-change (biggest rise or drop) or price (cheapest now)
+  --min-points  Priced orders an item needs to show a trend (default 2, or
+                1 with --compare)
+  --min-change  Only trends that rose at least this much, e.g. 10%; a
+                negative value keeps drops, e.g. -10%
+  --compare     Compare the named dish across restaurants
+
+--index shows your personal food-inflation index: what your usual basket
+(every item you order, weighted by how many you ordered) cost each month or
+year, with the first period at 100. Each period compares items with their
+previous price, so periods without repeat orders keep the last value.
+--cpi compares it with a reference CPI, rescaled to the same starting point
+(default: cpi.csv in the zocli config dir, if present). The file has
+date,value rows; dates are months (2024-01) or years (2024):
+
+  month,food_cpi
+  2024-01,190.4
+  2024-02,191.2
+
+Item prices come from the bill (orders synced with --details), from the total
+of single-item orders, or, for multi-item orders, from a least-squares fit of
+the restaurant's orders within 45 days. CONFIDENCE is lower when few orders
+contain the item or their totals disagree; trends skip prices below 50%.
+Bill prices are before taxes and fees, so they are tracked as a separate
+"(pre-tax)" trend rather than compared with prices from totals.
+`+filterOptions)
+}
+
+func PrintQueryUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli query
+
+Usage:
+  zocli query '[FILTER] [group by EXPR, ...] [select EXPR [as NAME], ...]
+               [order by NAME [asc|desc], ...] [limit N]'
+
+Order fields:  id, restaurant, status, placed_at, total, currency, item_count
+Item fields:   item, quantity, unit_price, item_total
+               (using one gives a row per ordered item; count() and
+               aggregates of order fields still count each order once)
+Functions:     year, month, day, weekday, hour, lower
+Aggregates:    count(), count(EXPR), sum, avg, min, max
+
+Filters compare with = != < <= > >= and combine with and, or, not.
+"~" matches a substring, or a regular expression written as "/.../";
+"!~" negates it. Text matches ignore case, and placed_at compares with
+dates such as "2024-01-15". Summing amounts in several currencies needs
+currency in group by. Without select, grouped queries show the keys and
+count(); order by takes a selected column's name or alias.
+
+Examples:
+  zocli query 'restaurant ~ "pizza" and total > 500 group by month(placed_at) select count(), sum(total)'
+  zocli query 'group by item select sum(quantity) as qty order by qty desc limit 10'
+  zocli query 'placed_at >= "2024-01-01" group by weekday(placed_at), currency'
+  zocli --output json query 'group by restaurant select count(), avg(total)'
+`)
+}
+
+func PrintBudgetUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli budget
+
+Usage:
+  zocli budget set --monthly AMOUNT [--restaurant X] [--category C]
+  zocli budget remove [--restaurant X] [--category C]
+  zocli budget status [--base CUR] [--rates FILE]
+
+Options:
+  --monthly     Monthly limit, e.g. 8000 or "AED 500" (default currency INR)
+  --restaurant  Only count restaurants matching this (substring or /regexp/)
+  --category    Only count orders in a category: late-night, morning,
+                afternoon, evening, weekday or weekend
+  --base        Convert orders to one currency before checking (see
+                'zocli help stats')
+
+Budgets are saved in the account's config; setting one with the same
+restaurant and category replaces it. Only orders in a budget's currency
+count towards it. status shows this month's spend against each limit and
+the month-end spend projected from the daily run rate so far, and exits
+with code 7 when any budget is exceeded.
+
+Examples:
+  zocli budget set --monthly 8000
+  zocli budget set --monthly 1500 --category late-night
+  zocli budget status || notify-send "Food budget exceeded"
+`)
+}
+
+func PrintAliasUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli alias
+
+Usage:
+  zocli alias add [--item] NAME COUNTED_AS
+  zocli alias remove [--item] NAME
+  zocli alias list
+  zocli alias suggest [--item] [--min 0.8]
+
+Options:
+  --item  Alias a dish name instead of a restaurant
+  --min   Minimum similarity (0-1) for suggestions
+
+stats, inflation, suggest, wrapped and dash count name variants as one:
+case, apostrophes and punctuation are ignored, as are a trailing
+"(Full)"-style qualifier and a " - Branch" suffix. Aliases merge names that
+differ in other ways; they are kept in aliases.json in the zocli config
+dir and shared by all accounts. suggest lists similar names that are still
+counted separately. inflation and dash keep a dish's "(Half)"-style
+qualifier so portion sizes are priced apart.
+
+Examples:
+  zocli alias add "Dominoes" "Domino's Pizza"
+  zocli alias add --item "Chkn Biryani" "Chicken Biryani"
+  zocli alias suggest --item
+`)
+}
+
+func PrintTagUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli tag
+
+Usage:
+  zocli tag ORDER_ID [+TAG ...] [-TAG ...]
+  zocli tag rules
+
+Orders are tagged from keywords in their restaurant and dish names, e.g.
+"biryani", "pizza" or "dessert". With changes, tag adds (+TAG) or removes
+(-TAG) tags on one order of the current account, overriding the rules;
+without, it shows the order's tags. rules lists the keyword rules.
+
+Add your own rules in tag_rules.json in the zocli config dir; keywords for a
+built-in tag are added to its defaults:
+
+  {"momos": {"restaurants": ["momo"], "items": ["momo", "dim sum"]},
+   "dessert": {"items": ["kulfi"]}}
+
+Keywords match the start of a word, ignoring case and punctuation. Tag names
+use lower case letters, digits and dashes.
+
+Examples:
+  zocli tag 123456789 +dessert -pizza
+  zocli stats --view categories --group month
 `)
 }
 
@@ -124,7 +390,93 @@ func PrintStatsUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli stats
 
 Usage:
-  zocli stats [--group month|year|none] [--view basic|spend|patterns|personal|all] [--top 5]
+  zocli stats [--group month|year|none] [--view basic|spend|patterns|personal|forecast|anomalies|categories|all] [--top 5] [--chart [--no-color] [--ascii]] [--accounts a,b|all] [--base CUR] [--rates FILE] [filters]
+
+Options:
+  --view      forecast projects next month's spend and orders from the
+              complete months so far (adjusting for the time of year once
+              there are two years of history); anomalies flags months and
+              weeks with unusually high or low spend (the current ones
+              only when high) and unusually large orders; categories breaks down orders and spend by tag
+              (see 'zocli help tag'), with the --top tags per period
+  --threshold Robust z-score an anomaly must exceed (default 3.5; lower
+              flags more)
+  --chart     Draw bar charts instead of tables for periods, weekdays, time
+              windows, top restaurants and items and tags, fitted to the
+              terminal width
+  --no-color  Draw charts without colors (also when NO_COLOR is set or the
+              output is not a terminal)
+  --ascii     Draw charts with plain ASCII characters
+  --accounts  Aggregate orders across several accounts
+  --base      Convert every amount to one currency (e.g. INR) before summarizing
+  --rates     Exchange rates file for --base (default: rates.json in the zocli
+              config dir)
+
+Orders in different currencies are summarized separately unless --base is
+given. The rates file maps each currency to its value in "base":
+
+  {"base": "INR", "rates": {"AED": 22.7, "USD": 83.2}}
+`+filterOptions)
+}
+
+func PrintHeatmapUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli heatmap
+
+Usage:
+  zocli heatmap [--year 2024] [--no-color] [--ascii] [--accounts a,b|all] [filters]
+
+Options:
+  --year      Calendar year to show (default: the last 12 months)
+  --no-color  Draw without colors (also when NO_COLOR is set or the output is
+              not a terminal)
+  --ascii     Draw with plain ASCII characters
+  --accounts  Aggregate orders across several accounts
+
+Draws a calendar of orders per day, one column per week from Monday to
+Sunday, shaded from no orders to the busiest days. Weeks that don't fit the
+terminal are dropped from the start. --output json|ndjson|csv|tsv lists the
+number of orders on every day instead.
+
+Examples:
+  zocli heatmap
+  zocli heatmap --year 2024 --restaurant pizza
+`+filterOptions)
+}
+
+func PrintSuggestUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli suggest
+
+Usage:
+  zocli suggest [--top 3] [--exclude-recent 3d] [--budget 400] [--new] [--seed N]
+
+Options:
+  --top             Number of suggestions
+  --exclude-recent  Skip restaurants ordered from since then (3d, 2w,
+                    yesterday, this-week)
+  --budget          Only restaurants whose average order costs at most this,
+                    e.g. 400 or "AED 50"
+  --new             Suggest places ordered from only once or twice
+  --seed            Random seed, for a reproducible pick
+
+Restaurants score higher the more often you ordered from them (recent orders
+count more), lower if you ordered from them in the last few days, and higher
+when you usually order from them at this time of day or on this weekday. A
+little randomness keeps suggestions varied; each comes with a dish picked
+from what you usually order there and the reasons it was suggested.
+`)
+}
+
+func PrintDashUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli dash
+
+Usage:
+  zocli dash [--base CUR] [--rates FILE] [--cpi FILE]
+
+Options:
+  --base   Convert every amount to one currency before summarizing
+  --rates  Exchange rates file for --base (see 'zocli help stats')
+  --cpi    Reference CPI for the inflation tab's index chart (see 'zocli help
+           inflation')
 `)
 }
 
@@ -132,7 +484,54 @@ func PrintConfigUsage(w io.Writer) {
 	fmt.Fprint(w, `zocli config
 
 Usage:
-  zocli config
+  zocli config [--timezone ZONE]
+
+Options:
+  --timezone  Set the account's region timezone, an IANA name such as
+              Asia/Dubai (default: Asia/Kolkata). Order dates from Zomato
+              carry no zone and are read in this one; stored orders are
+              updated to match. Run without options to show the paths
+              and update orders stored by older versions.
+`)
+}
+
+func PrintStoreUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli store
+
+Usage:
+  zocli store restore [--generation N] [--config]
+  zocli store migrate --to json|sqlite
+
+Options:
+  --generation  Backup generation to restore (1 = most recent). Without it,
+                the available backups are listed.
+  --config      Operate on config.json instead of orders.json
+  --to          Backend to copy stored orders into; it becomes the default
+
+Examples:
+  zocli store restore
+  zocli store restore --generation 2
+  zocli store migrate --to sqlite
+`)
+}
+
+func PrintAccountsUsage(w io.Writer) {
+	fmt.Fprint(w, `zocli accounts
+
+Usage:
+  zocli accounts list
+  zocli accounts add NAME
+  zocli accounts remove [--purge] NAME
+  zocli accounts default [NAME]
+
+Each account keeps its own cookie and orders. Select one per command with
+--account NAME or ZOCLI_ACCOUNT; accounts used with 'auth login' are added
+automatically.
+
+Examples:
+  zocli auth login --account work
+  zocli accounts default work
+  zocli stats --accounts all
 `)
 }
 
@@ -140,14 +539,36 @@ func PrintCommandUsage(w io.Writer, cmd string) bool {
 	switch cmd {
 	case "auth":
 		PrintAuthUsage(w)
+	case "accounts":
+		PrintAccountsUsage(w)
 	case "sync":
 		PrintSyncUsage(w)
 	case "orders":
 		PrintOrdersUsage(w)
 	case "stats":
 		PrintStatsUsage(w)
+	case "inflation":
+		PrintInflationUsage(w)
+	case "query":
+		PrintQueryUsage(w)
+	case "budget":
+		PrintBudgetUsage(w)
+	case "alias":
+		PrintAliasUsage(w)
+	case "tag":
+		PrintTagUsage(w)
+	case "heatmap":
+		PrintHeatmapUsage(w)
+	case "suggest":
+		PrintSuggestUsage(w)
+	case "dash":
+		PrintDashUsage(w)
 	case "config":
 		PrintConfigUsage(w)
+	case "store":
+		PrintStoreUsage(w)
+	case "reparse":
+		PrintReparseUsage(w)
 	default:
 		return false
 	}
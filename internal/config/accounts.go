@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+)
+
+// DefaultAccount is the unnamed account that lives directly in the zocli
+// config dir, where single-account installs keep their data.
+const DefaultAccount = "default"
+
+var accountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Accounts is the registry of named accounts, stored in accounts.json.
+type Accounts struct {
+	Default string   `json:"default,omitempty"`
+	Names   []string `json:"accounts"`
+	// Sample names the account 'sync --mock' created for its fake orders.
+	// It is left out of All so sample data never mixes with real totals.
+	Sample string `json:"sample,omitempty"`
+}
+
+// ValidateAccountName checks that name is usable as a directory name.
+func ValidateAccountName(name string) error {
+	if !accountNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid account name %q: use up to 32 lowercase letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// BaseDir is the zocli directory inside the user config dir.
+func BaseDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "zocli"), nil
+}
+
+// AccountDir returns the directory holding an account's config and orders.
+// The default account uses BaseDir itself.
+func AccountDir(account string) (string, error) {
+	base, err := BaseDir()
+	if err != nil {
+		return "", err
+	}
+	if account == "" || account == DefaultAccount {
+		return base, nil
+	}
+	if err := ValidateAccountName(account); err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "accounts", account), nil
+}
+
+// PathFor returns the config.json path for an account.
+func PathFor(account string) (string, error) {
+	if account == "" || account == DefaultAccount {
+		return DefaultPath()
+	}
+	dir, err := AccountDir(account)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+func AccountsPath() (string, error) {
+	base, err := BaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "accounts.json"), nil
+}
+
+// LoadAccounts reads the registry. A missing file yields an empty registry.
+func LoadAccounts(path string) (Accounts, error) {
+	var accounts Accounts
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return accounts, nil
+		}
+		return accounts, err
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return accounts, err
+	}
+	return accounts, nil
+}
+
+func SaveAccounts(path string, accounts Accounts) error {
+	sort.Strings(accounts.Names)
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, data, 0o600, backupGenerations)
+}
+
+// Has reports whether name is a known account. The default account always
+// exists.
+func (a Accounts) Has(name string) bool {
+	if name == DefaultAccount {
+		return true
+	}
+	for _, n := range a.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Add registers name and reports whether it was new.
+func (a *Accounts) Add(name string) (bool, error) {
+	if err := ValidateAccountName(name); err != nil {
+		return false, err
+	}
+	if a.Has(name) {
+		return false, nil
+	}
+	a.Names = append(a.Names, name)
+	return true, nil
+}
+
+// Remove unregisters name and reports whether it was registered.
+func (a *Accounts) Remove(name string) bool {
+	for i, n := range a.Names {
+		if n == name {
+			a.Names = append(a.Names[:i], a.Names[i+1:]...)
+			if a.Default == name {
+				a.Default = ""
+			}
+			if a.Sample == name {
+				a.Sample = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// All lists every account with real orders, starting with the default one.
+func (a Accounts) All() []string {
+	names := []string{DefaultAccount}
+	for _, n := range a.Names {
+		if n != DefaultAccount && n != a.Sample {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// Selected is the account used when none is given explicitly.
+func (a Accounts) Selected() string {
+	if a.Default == "" {
+		return DefaultAccount
+	}
+	return a.Default
+}
@@ -5,10 +5,35 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+
+	"github.com/maheshrijal/zocli/internal/budget"
+	"github.com/maheshrijal/zocli/internal/fileutil"
+	"github.com/maheshrijal/zocli/internal/tags"
 )
 
+// backupGenerations is how many previous versions of config.json are kept.
+const backupGenerations = 3
+
 type Config struct {
-	Cookie string `json:"cookie"`
+	// Cookie is only kept here with the plaintext secrets backend; otherwise
+	// it lives in the keyring or an encrypted file (see secrets.go).
+	Cookie string `json:"cookie,omitempty"`
+	// Secrets selects where the cookie is stored: "keyring" (default),
+	// "file" or "plaintext".
+	Secrets string `json:"secrets,omitempty"`
+	// Store selects the order storage backend: "json" (default) or "sqlite".
+	Store string `json:"store,omitempty"`
+	// Timezone is the IANA name of the account's region timezone, which
+	// order dates are interpreted in. Empty means Asia/Kolkata.
+	Timezone string `json:"timezone,omitempty"`
+	// OrdersTimezone records the zone stored order times were last
+	// interpreted in. Stores written before it existed used the machine's
+	// local zone and are migrated once when it differs from Timezone.
+	OrdersTimezone string `json:"orders_timezone,omitempty"`
+	// Budgets are the monthly spending limits checked by 'zocli budget'.
+	Budgets []budget.Budget `json:"budgets,omitempty"`
+	// OrderTags are manual tag edits made with 'zocli tag', by order ID.
+	OrderTags map[string]tags.Manual `json:"order_tags,omitempty"`
 }
 
 func DefaultPath() (string, error) {
@@ -37,14 +62,32 @@ func Save(path string, cfg Config) error {
 	if path == "" {
 		return errors.New("config path is required")
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return err
-	}
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o600)
+	return fileutil.WriteAtomic(path, data, 0o600, backupGenerations)
+}
+
+// Update loads the config at path, applies fn and saves the result. A missing
+// file starts from an empty config.
+func Update(path string, fn func(*Config)) error {
+	cfg, err := Load(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fn(&cfg)
+	return Save(path, cfg)
+}
+
+// Backups lists the rolling backups of the config file, newest first.
+func Backups(path string) ([]fileutil.Backup, error) {
+	return fileutil.Backups(path)
+}
+
+// Restore replaces the config file with a backup generation.
+func Restore(path string, generation int) error {
+	return fileutil.Restore(path, generation, 0o600, backupGenerations)
 }
 
 func preferPath(newPath, oldPath string, perm os.FileMode) string {
@@ -82,3 +82,58 @@ func TestConfig_PreferPath(t *testing.T) {
 		t.Errorf("Case 3: got %s, want %s", got, newP)
 	}
 }
+
+func TestAccounts_AddRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+
+	accounts, err := LoadAccounts(path)
+	if err != nil {
+		t.Fatalf("LoadAccounts missing file failed: %v", err)
+	}
+	if got := accounts.Selected(); got != DefaultAccount {
+		t.Errorf("Selected = %q, want %q", got, DefaultAccount)
+	}
+
+	if added, err := accounts.Add("work"); err != nil || !added {
+		t.Fatalf("Add(work) = %v, %v; want true, nil", added, err)
+	}
+	if added, _ := accounts.Add("work"); added {
+		t.Error("Add(work) twice reported a new account")
+	}
+	if _, err := accounts.Add("Bad Name"); err == nil {
+		t.Error("Add with invalid name: succeeded, want error")
+	}
+	accounts.Default = "work"
+	if err := SaveAccounts(path, accounts); err != nil {
+		t.Fatalf("SaveAccounts failed: %v", err)
+	}
+
+	loaded, err := LoadAccounts(path)
+	if err != nil {
+		t.Fatalf("LoadAccounts failed: %v", err)
+	}
+	if !loaded.Has("work") || loaded.Selected() != "work" {
+		t.Errorf("Loaded = %+v, want work as default", loaded)
+	}
+	if got := loaded.All(); len(got) != 2 || got[0] != DefaultAccount {
+		t.Errorf("All = %v, want [default work]", got)
+	}
+
+	if _, err := loaded.Add("sample"); err != nil {
+		t.Fatal(err)
+	}
+	loaded.Sample = "sample"
+	if got := loaded.All(); len(got) != 2 || !loaded.Has("sample") {
+		t.Errorf("All = %v, want the sample account left out", got)
+	}
+	if !loaded.Remove("sample") || loaded.Sample != "" {
+		t.Errorf("after Remove(sample), Sample = %q, want empty", loaded.Sample)
+	}
+
+	if !loaded.Remove("work") {
+		t.Error("Remove(work) = false, want true")
+	}
+	if loaded.Selected() != DefaultAccount {
+		t.Errorf("Selected after remove = %q, want %q", loaded.Selected(), DefaultAccount)
+	}
+}
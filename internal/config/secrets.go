@@ -0,0 +1,421 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+	"github.com/zalando/go-keyring"
+)
+
+// Backends for storing the session cookie.
+const (
+	SecretsKeyring   = "keyring"
+	SecretsFile      = "file"
+	SecretsPlaintext = "plaintext"
+)
+
+const (
+	keyringService   = "zocli"
+	secretFileName   = "cookie.enc"
+	pbkdf2Iterations = 600_000
+)
+
+// ErrSecretNotFound is returned when no cookie has been stored.
+var ErrSecretNotFound = errors.New("no saved cookie")
+
+// SecretOptions carries what the secret backends need besides the config.
+type SecretOptions struct {
+	// Account names the keyring entry.
+	Account string
+	// Passphrase supplies the passphrase for the encrypted-file backend.
+	Passphrase func() (string, error)
+}
+
+// SecretStore keeps one account's session cookie.
+type SecretStore interface {
+	Get() (string, error)
+	Set(secret string) error
+	Delete() error
+}
+
+// SecretBackend returns the configured backend, defaulting to the keyring.
+func (c Config) SecretBackend() string {
+	backend := strings.ToLower(strings.TrimSpace(c.Secrets))
+	if backend == "" {
+		return SecretsKeyring
+	}
+	return backend
+}
+
+// OpenSecrets returns the secret store selected in cfg for the config at path.
+func OpenSecrets(path string, cfg Config, opts SecretOptions) (SecretStore, error) {
+	switch cfg.SecretBackend() {
+	case SecretsKeyring:
+		user := opts.Account
+		if user == "" {
+			user = DefaultAccount
+		}
+		return keyringSecret{user: user}, nil
+	case SecretsFile:
+		return fileSecret{path: filepath.Join(filepath.Dir(path), secretFileName), passphrase: opts.Passphrase}, nil
+	case SecretsPlaintext:
+		return plaintextSecret{path: path}, nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend: %s (use keyring, file or plaintext)", cfg.Secrets)
+	}
+}
+
+// SecretLocation describes where the cookie for the config at path is kept.
+func SecretLocation(path string) string {
+	cfg, _ := Load(path)
+	switch cfg.SecretBackend() {
+	case SecretsKeyring:
+		return "the OS keyring"
+	case SecretsFile:
+		return filepath.Join(filepath.Dir(path), secretFileName) + " (encrypted)"
+	default:
+		return path
+	}
+}
+
+// LoadCookie returns the session cookie for the config at path. A cookie
+// still sitting in config.json is returned as is; see MigrateCookie.
+func LoadCookie(path string, opts SecretOptions) (string, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(cfg.Cookie) != "" {
+		return cfg.Cookie, nil
+	}
+	secrets, err := OpenSecrets(path, cfg, opts)
+	if err != nil {
+		return "", err
+	}
+	return secrets.Get()
+}
+
+// SaveCookie stores cookie in the configured backend.
+func SaveCookie(path, cookie string, opts SecretOptions) error {
+	cfg, err := Load(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	secrets, err := OpenSecrets(path, cfg, opts)
+	if err != nil {
+		return err
+	}
+	if err := secrets.Set(cookie); err != nil {
+		return err
+	}
+	if cfg.SecretBackend() == SecretsPlaintext {
+		return nil
+	}
+	if cfg.Cookie != "" {
+		return scrubPlaintextCookie(path)
+	}
+	// Keep a config file for the account even though the cookie lives elsewhere.
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Save(path, cfg)
+	}
+	return nil
+}
+
+// DeleteCookie removes the cookie from the configured backend and from
+// config.json.
+func DeleteCookie(path string, opts SecretOptions) error {
+	cfg, err := Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	secrets, err := OpenSecrets(path, cfg, opts)
+	if err != nil {
+		return err
+	}
+	if err := secrets.Delete(); err != nil && !errors.Is(err, ErrSecretNotFound) {
+		return err
+	}
+	if cfg.Cookie != "" {
+		return scrubPlaintextCookie(path)
+	}
+	return nil
+}
+
+// MigrateCookie moves a plaintext cookie from config.json into the
+// configured secret backend. It reports whether anything was moved.
+func MigrateCookie(path string, opts SecretOptions) (bool, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if cfg.SecretBackend() == SecretsPlaintext || strings.TrimSpace(cfg.Cookie) == "" {
+		return false, nil
+	}
+	secrets, err := OpenSecrets(path, cfg, opts)
+	if err != nil {
+		return false, err
+	}
+	if err := secrets.Set(cfg.Cookie); err != nil {
+		return false, err
+	}
+	if err := scrubPlaintextCookie(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SwitchSecrets changes the secrets backend, moving the current cookie over.
+// The cookie is written to the new backend and read back before the config
+// is switched and the old copy deleted, so a failure leaves the old backend
+// as it was.
+func SwitchSecrets(path, backend string, opts SecretOptions) error {
+	cfg, err := Load(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	next := cfg
+	next.Secrets = backend
+	if next.SecretBackend() == cfg.SecretBackend() {
+		return nil
+	}
+	newSecrets, err := OpenSecrets(path, next, opts)
+	if err != nil {
+		return err
+	}
+	oldSecrets, err := OpenSecrets(path, cfg, opts)
+	if err != nil {
+		return err
+	}
+	cookie, err := LoadCookie(path, opts)
+	if err != nil && !errors.Is(err, ErrSecretNotFound) && !os.IsNotExist(err) {
+		return err
+	}
+
+	if cookie != "" && next.SecretBackend() != SecretsPlaintext {
+		if err := newSecrets.Set(cookie); err != nil {
+			return err
+		}
+		if got, err := newSecrets.Get(); err != nil || got != cookie {
+			newSecrets.Delete()
+			if err == nil {
+				err = errors.New("stored cookie does not match")
+			}
+			return fmt.Errorf("check cookie in %s backend: %w", backend, err)
+		}
+	}
+	// The plaintext backend is config.json itself, so its cookie is written
+	// in the same save that switches the backend.
+	err = Update(path, func(c *Config) {
+		c.Secrets = backend
+		if next.SecretBackend() == SecretsPlaintext && cookie != "" {
+			c.Cookie = cookie
+		}
+	})
+	if err != nil {
+		if cookie != "" && next.SecretBackend() != SecretsPlaintext {
+			newSecrets.Delete()
+		}
+		return err
+	}
+
+	if cfg.SecretBackend() != SecretsPlaintext {
+		if err := oldSecrets.Delete(); err != nil && !errors.Is(err, ErrSecretNotFound) {
+			return err
+		}
+	}
+	if cfg.Cookie != "" && next.SecretBackend() != SecretsPlaintext {
+		return scrubPlaintextCookie(path)
+	}
+	return nil
+}
+
+// scrubPlaintextCookie clears the cookie from config.json and drops the
+// rolling backups, which would otherwise still contain it.
+func scrubPlaintextCookie(path string) error {
+	if err := Update(path, func(cfg *Config) { cfg.Cookie = "" }); err != nil {
+		return err
+	}
+	return fileutil.RemoveBackups(path)
+}
+
+type keyringSecret struct {
+	user string
+}
+
+func (k keyringSecret) Get() (string, error) {
+	secret, err := keyring.Get(keyringService, k.user)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrSecretNotFound
+	}
+	if err != nil {
+		return "", keyringError(err)
+	}
+	return secret, nil
+}
+
+func (k keyringSecret) Set(secret string) error {
+	if err := keyring.Set(keyringService, k.user, secret); err != nil {
+		return keyringError(err)
+	}
+	return nil
+}
+
+func (k keyringSecret) Delete() error {
+	err := keyring.Delete(keyringService, k.user)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrSecretNotFound
+	}
+	if err != nil {
+		return keyringError(err)
+	}
+	return nil
+}
+
+func keyringError(err error) error {
+	return fmt.Errorf("OS keyring unavailable (%w); on headless machines use 'zocli auth secrets file'", err)
+}
+
+type plaintextSecret struct {
+	path string
+}
+
+func (p plaintextSecret) Get() (string, error) {
+	cfg, err := Load(p.path)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(cfg.Cookie) == "" {
+		return "", ErrSecretNotFound
+	}
+	return cfg.Cookie, nil
+}
+
+func (p plaintextSecret) Set(secret string) error {
+	return Update(p.path, func(cfg *Config) { cfg.Cookie = secret })
+}
+
+func (p plaintextSecret) Delete() error {
+	return Update(p.path, func(cfg *Config) { cfg.Cookie = "" })
+}
+
+// fileSecret encrypts the cookie with AES-256-GCM under a key derived from
+// a passphrase with PBKDF2-SHA256.
+type fileSecret struct {
+	path       string
+	passphrase func() (string, error)
+}
+
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (f fileSecret) Get() (string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+	var enc encryptedFile
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return "", fmt.Errorf("read %s: %w", f.path, err)
+	}
+	passphrase, err := f.getPassphrase()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, enc.Nonce, enc.Ciphertext, nil)
+	if err != nil {
+		return "", errors.New("could not decrypt saved cookie: wrong passphrase?")
+	}
+	return string(plain), nil
+}
+
+func (f fileSecret) Set(secret string) error {
+	passphrase, err := f.getPassphrase()
+	if err != nil {
+		return err
+	}
+	enc := encryptedFile{
+		Version:    1,
+		Iterations: pbkdf2Iterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return err
+	}
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return err
+	}
+	enc.Ciphertext = gcm.Seal(nil, enc.Nonce, []byte(secret), nil)
+
+	data, err := json.MarshalIndent(enc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(f.path, data, 0o600, 0)
+}
+
+func (f fileSecret) Delete() error {
+	err := os.Remove(f.path)
+	if os.IsNotExist(err) {
+		return ErrSecretNotFound
+	}
+	return err
+}
+
+func (f fileSecret) getPassphrase() (string, error) {
+	if f.passphrase == nil {
+		return "", errors.New("a passphrase is required for the encrypted cookie file; set ZOCLI_PASSPHRASE")
+	}
+	passphrase, err := f.passphrase()
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+	return passphrase, nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
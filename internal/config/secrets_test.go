@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestMigrateCookie_ToKeyring(t *testing.T) {
+	keyring.MockInit()
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := Save(cfgPath, Config{Cookie: "legacy-cookie"}); err != nil {
+		t.Fatal(err)
+	}
+	// A second save leaves a backup that still holds the plaintext cookie.
+	if err := Save(cfgPath, Config{Cookie: "legacy-cookie"}); err != nil {
+		t.Fatal(err)
+	}
+	opts := SecretOptions{Account: "work"}
+
+	migrated, err := MigrateCookie(cfgPath, opts)
+	if err != nil || !migrated {
+		t.Fatalf("MigrateCookie = %v, %v; want true, nil", migrated, err)
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "legacy-cookie") {
+		t.Error("config.json still contains the cookie after migration")
+	}
+	if backups, _ := Backups(cfgPath); len(backups) != 0 {
+		t.Errorf("config backups left after migration: %v", backups)
+	}
+
+	got, err := LoadCookie(cfgPath, opts)
+	if err != nil || got != "legacy-cookie" {
+		t.Errorf("LoadCookie = %q, %v; want legacy-cookie", got, err)
+	}
+
+	if err := DeleteCookie(cfgPath, opts); err != nil {
+		t.Fatalf("DeleteCookie failed: %v", err)
+	}
+	if _, err := LoadCookie(cfgPath, opts); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("LoadCookie after delete err = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestSecrets_EncryptedFile(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	if err := Save(cfgPath, Config{Secrets: SecretsFile}); err != nil {
+		t.Fatal(err)
+	}
+	pass := "correct horse"
+	opts := SecretOptions{Passphrase: func() (string, error) { return pass, nil }}
+
+	if err := SaveCookie(cfgPath, "file-cookie", opts); err != nil {
+		t.Fatalf("SaveCookie failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, secretFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "file-cookie") {
+		t.Error("encrypted file contains the cookie in plaintext")
+	}
+
+	got, err := LoadCookie(cfgPath, opts)
+	if err != nil || got != "file-cookie" {
+		t.Errorf("LoadCookie = %q, %v; want file-cookie", got, err)
+	}
+
+	pass = "wrong"
+	if _, err := LoadCookie(cfgPath, opts); err == nil {
+		t.Error("LoadCookie with wrong passphrase: succeeded, want error")
+	}
+}
+
+func TestSwitchSecrets_ToPlaintext(t *testing.T) {
+	keyring.MockInit()
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	opts := SecretOptions{Account: "default"}
+	if err := SaveCookie(cfgPath, "kept-cookie", opts); err != nil {
+		t.Fatalf("SaveCookie failed: %v", err)
+	}
+
+	if err := SwitchSecrets(cfgPath, SecretsPlaintext, opts); err != nil {
+		t.Fatalf("SwitchSecrets failed: %v", err)
+	}
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cookie != "kept-cookie" || cfg.Secrets != SecretsPlaintext {
+		t.Errorf("config = %+v, want plaintext cookie", cfg)
+	}
+	if _, err := keyring.Get(keyringService, "default"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("keyring entry left behind, err = %v", err)
+	}
+}
+
+func TestSwitchSecrets_FailureKeepsOldBackend(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	cfg := Config{Secrets: SecretsPlaintext, Cookie: "kept-cookie"}
+	if err := Save(cfgPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfgPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	opts := SecretOptions{Passphrase: func() (string, error) {
+		return "", errors.New("passphrase required")
+	}}
+
+	if err := SwitchSecrets(cfgPath, SecretsFile, opts); err == nil {
+		t.Fatal("SwitchSecrets without a passphrase: succeeded, want error")
+	}
+	got, err := Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cookie != "kept-cookie" || got.SecretBackend() != SecretsPlaintext {
+		t.Errorf("config after failed switch = %+v, want the plaintext cookie kept", got)
+	}
+	if backups, _ := Backups(cfgPath); len(backups) == 0 {
+		t.Error("config backups removed by a failed switch")
+	}
+	if _, err := os.Stat(filepath.Join(dir, secretFileName)); !os.IsNotExist(err) {
+		t.Errorf("encrypted cookie file left by a failed switch, err = %v", err)
+	}
+}
+
+func TestSwitchSecrets_ToFile(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := Save(cfgPath, Config{Secrets: SecretsPlaintext, Cookie: "moved-cookie"}); err != nil {
+		t.Fatal(err)
+	}
+	opts := SecretOptions{Passphrase: func() (string, error) { return "pass", nil }}
+
+	if err := SwitchSecrets(cfgPath, SecretsFile, opts); err != nil {
+		t.Fatalf("SwitchSecrets failed: %v", err)
+	}
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cookie != "" || cfg.SecretBackend() != SecretsFile {
+		t.Errorf("config = %+v, want file backend without a plaintext cookie", cfg)
+	}
+	if got, err := LoadCookie(cfgPath, opts); err != nil || got != "moved-cookie" {
+		t.Errorf("LoadCookie = %q, %v; want moved-cookie", got, err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 
 	"github.com/maheshrijal/zocli/internal/zomato"
@@ -22,6 +21,7 @@ func ToCSV(orders []zomato.Order, w io.Writer) error {
 		"Date",
 		"Status",
 		"Total",
+		"Currency",
 		"Items",
 	}); err != nil {
 		return err
@@ -38,7 +38,8 @@ func ToCSV(orders []zomato.Order, w io.Writer) error {
 			o.Restaurant,
 			o.PlacedAt.Format("2006-01-02 15:04:05"),
 			o.Status,
-			o.Total,
+			o.Total.Number(),
+			o.Total.Currency,
 			strings.Join(items, "; "),
 		}
 		if err := cw.Write(record); err != nil {
@@ -56,13 +57,3 @@ func ToJSON(orders []zomato.Order, w io.Writer) error {
 	enc.SetIndent("", "  ")
 	return enc.Encode(orders)
 }
-
-// Helper to convert currency string to float for external usage if needed
-func parseCurrency(s string) float64 {
-	// Removes non-numeric (except dot) and parses
-	s = strings.ReplaceAll(s, "₹", "")
-	s = strings.ReplaceAll(s, ",", "")
-	s = strings.TrimSpace(s)
-	f, _ := strconv.ParseFloat(s, 64)
-	return f
-}
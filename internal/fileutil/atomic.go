@@ -0,0 +1,163 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backup describes one rolling backup generation of a file.
+type Backup struct {
+	Generation int
+	Path       string
+	Size       int64
+	ModTime    time.Time
+}
+
+// WriteAtomic writes data to path through a temporary file that is fsynced
+// and renamed into place, so readers never observe a truncated file. When
+// generations > 0 the previous contents are kept as path.1, path.2, ...,
+// dropping anything older than path.<generations>.
+func WriteAtomic(path string, data []byte, perm os.FileMode, generations int) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if generations > 0 {
+		if err := rotate(path, perm, generations); err != nil {
+			return fmt.Errorf("rotate backups: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// Backups lists the existing backup generations of path, newest first.
+func Backups(path string) ([]Backup, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for _, match := range matches {
+		gen, err := strconv.Atoi(strings.TrimPrefix(match, path+"."))
+		if err != nil || gen <= 0 {
+			continue
+		}
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		backups = append(backups, Backup{
+			Generation: gen,
+			Path:       match,
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Generation < backups[j].Generation
+	})
+	return backups, nil
+}
+
+// Restore replaces path with the given backup generation. The current file
+// is rotated into the backups first, so a restore can itself be undone.
+func Restore(path string, generation int, perm os.FileMode, generations int) error {
+	if generation <= 0 {
+		return errors.New("backup generation must be 1 or greater")
+	}
+	data, err := os.ReadFile(BackupPath(path, generation))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no backup generation %d for %s", generation, path)
+		}
+		return err
+	}
+	return WriteAtomic(path, data, perm, generations)
+}
+
+// BackupPath returns the file name used for a backup generation of path.
+func BackupPath(path string, generation int) string {
+	return fmt.Sprintf("%s.%d", path, generation)
+}
+
+func rotate(path string, perm os.FileMode, generations int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.Remove(BackupPath(path, generations)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for gen := generations - 1; gen >= 1; gen-- {
+		err := os.Rename(BackupPath(path, gen), BackupPath(path, gen+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	// Copy rather than rename so path always exists until the new file lands.
+	return os.WriteFile(BackupPath(path, 1), data, perm)
+}
+
+// syncDir flushes the directory entry after a rename. Not every platform
+// supports this, so failures are ignored.
+func syncDir(dir string) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_ = f.Sync()
+}
+
+// RemoveBackups deletes every backup generation of path.
+func RemoveBackups(path string) error {
+	backups, err := Backups(path)
+	if err != nil {
+		return err
+	}
+	for _, b := range backups {
+		if err := os.Remove(b.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
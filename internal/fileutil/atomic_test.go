@@ -0,0 +1,69 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAtomic_Rotates(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "orders.json")
+
+	for _, content := range []string{"one", "two", "three", "four"} {
+		if err := WriteAtomic(path, []byte(content), 0o600, 2); err != nil {
+			t.Fatalf("WriteAtomic(%q) failed: %v", content, err)
+		}
+	}
+
+	assertContent(t, path, "four")
+	assertContent(t, BackupPath(path, 1), "three")
+	assertContent(t, BackupPath(path, 2), "two")
+	if _, err := os.Stat(BackupPath(path, 3)); !os.IsNotExist(err) {
+		t.Errorf("generation 3 should not exist, stat err = %v", err)
+	}
+
+	backups, err := Backups(path)
+	if err != nil {
+		t.Fatalf("Backups failed: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Generation != 1 || backups[1].Generation != 2 {
+		t.Errorf("Backups = %+v, want generations 1 and 2", backups)
+	}
+
+	// No temp files should be left behind.
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 3 {
+		t.Errorf("Directory has %d entries, want 3", len(entries))
+	}
+}
+
+func TestRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, content := range []string{"good", "bad"} {
+		if err := WriteAtomic(path, []byte(content), 0o600, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Restore(path, 1, 0o600, 3); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	assertContent(t, path, "good")
+	assertContent(t, BackupPath(path, 1), "bad")
+
+	if err := Restore(path, 9, 0o600, 3); err == nil {
+		t.Error("Restore of missing generation: succeeded, want error")
+	}
+}
+
+func assertContent(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, want)
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var relativePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// ParseDate resolves a date expression to the period [start, end) it
+// covers. --since uses the start and --until the end, so both bounds
+// include the named day, month or year. Accepted forms:
+//
+//	2024-01-15, 2024-01, 2024       calendar day, month or year
+//	30d, 2w, 6m, 1y                 that long before now (an instant)
+//	today, yesterday
+//	this-week, last-week            weeks start on Monday
+//	this-month, last-month
+//	this-year, last-year
+//
+// Calendar periods are taken in now's location.
+func ParseDate(value string, now time.Time) (time.Time, time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	loc := now.Location()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	if m := relativePattern.FindStringSubmatch(value); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		var at time.Time
+		switch m[2] {
+		case "d":
+			at = now.AddDate(0, 0, -n)
+		case "w":
+			at = now.AddDate(0, 0, -7*n)
+		case "m":
+			at = now.AddDate(0, -n, 0)
+		case "y":
+			at = now.AddDate(-n, 0, 0)
+		}
+		return at, at, nil
+	}
+
+	switch value {
+	case "today":
+		return day, day.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return day.AddDate(0, 0, -1), day, nil
+	case "this-week", "last-week":
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		start := day.AddDate(0, 0, -offset)
+		if value == "last-week" {
+			start = start.AddDate(0, 0, -7)
+		}
+		return start, start.AddDate(0, 0, 7), nil
+	case "this-month", "last-month":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		if value == "last-month" {
+			start = start.AddDate(0, -1, 0)
+		}
+		return start, start.AddDate(0, 1, 0), nil
+	case "this-year", "last-year":
+		start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+		if value == "last-year" {
+			start = start.AddDate(-1, 0, 0)
+		}
+		return start, start.AddDate(1, 0, 0), nil
+	}
+
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+	for _, l := range layouts {
+		if start, err := time.ParseInLocation(l.layout, value, loc); err == nil {
+			return start, start.AddDate(l.years, l.months, l.days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q (use 2024-01-15, 2024-01, 30d or last-month)", value)
+}
@@ -0,0 +1,239 @@
+// Package filter selects and orders stored orders for the report commands.
+package filter
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Sort keys accepted by Options.Sort.
+const (
+	SortDate       = "date"
+	SortTotal      = "total"
+	SortRestaurant = "restaurant"
+)
+
+// Options selects a subset of orders. Zero fields match everything.
+type Options struct {
+	Since      time.Time // inclusive
+	Until      time.Time // exclusive
+	Restaurant Matcher
+	Item       Matcher
+	Status     string // case-insensitive exact match
+	// Min and Max bound the order total. Amounts in different currencies
+	// are never compared: when either is set, orders in another currency
+	// don't match.
+	Min, Max *zomato.Money
+	// Sort is one of SortDate (newest first, the default), SortTotal
+	// (highest first within each currency) or SortRestaurant (A-Z);
+	// Reverse flips it.
+	Sort    string
+	Reverse bool
+	// Limit keeps the first Limit orders after sorting; 0 keeps all.
+	Limit int
+}
+
+// Matcher matches names case-insensitively, by substring or, for patterns
+// written as /regexp/, by regular expression.
+type Matcher struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// NewMatcher parses a substring or /regexp/ pattern.
+func NewMatcher(pattern string) (Matcher, error) {
+	pattern = strings.TrimSpace(pattern)
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		return Matcher{pattern: pattern, re: re}, nil
+	}
+	return Matcher{pattern: strings.ToLower(pattern)}, nil
+}
+
+// IsZero reports whether the matcher matches everything.
+func (m Matcher) IsZero() bool {
+	return m.pattern == ""
+}
+
+// Substring returns the lower-cased substring the matcher looks for; ok is
+// false for regular expressions.
+func (m Matcher) Substring() (sub string, ok bool) {
+	return m.pattern, m.re == nil
+}
+
+// Match reports whether name matches.
+func (m Matcher) Match(name string) bool {
+	if m.re != nil {
+		return m.re.MatchString(name)
+	}
+	return strings.Contains(strings.ToLower(name), m.pattern)
+}
+
+// Match reports whether an order passes every filter in o.
+func (o Options) Match(order zomato.Order) bool {
+	if !o.Since.IsZero() && (order.PlacedAt.IsZero() || order.PlacedAt.Before(o.Since)) {
+		return false
+	}
+	if !o.Until.IsZero() && (order.PlacedAt.IsZero() || !order.PlacedAt.Before(o.Until)) {
+		return false
+	}
+	if !o.Restaurant.IsZero() && !o.Restaurant.Match(order.Restaurant) {
+		return false
+	}
+	if !o.Item.IsZero() {
+		found := false
+		for _, item := range order.Items {
+			if o.Item.Match(item.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if o.Status != "" && !strings.EqualFold(strings.TrimSpace(order.Status), o.Status) {
+		return false
+	}
+	if o.Min != nil && (order.Total.Currency != o.Min.Currency || order.Total.Amount < o.Min.Amount) {
+		return false
+	}
+	if o.Max != nil && (order.Total.Currency != o.Max.Currency || order.Total.Amount > o.Max.Amount) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the matching orders, sorted and limited as o asks. The
+// input slice is not modified.
+func (o Options) Apply(orders []zomato.Order) []zomato.Order {
+	out := make([]zomato.Order, 0, len(orders))
+	for _, order := range orders {
+		if o.Match(order) {
+			out = append(out, order)
+		}
+	}
+
+	var less func(a, b zomato.Order) bool
+	switch o.Sort {
+	case SortTotal:
+		less = func(a, b zomato.Order) bool {
+			if a.Total.Currency != b.Total.Currency {
+				return a.Total.Currency < b.Total.Currency
+			}
+			return a.Total.Amount > b.Total.Amount
+		}
+	case SortRestaurant:
+		less = func(a, b zomato.Order) bool {
+			return strings.ToLower(a.Restaurant) < strings.ToLower(b.Restaurant)
+		}
+	default:
+		less = func(a, b zomato.Order) bool { return a.PlacedAt.After(b.PlacedAt) }
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if o.Reverse {
+			return less(out[j], out[i])
+		}
+		return less(out[i], out[j])
+	})
+
+	if o.Limit > 0 && len(out) > o.Limit {
+		out = out[:o.Limit]
+	}
+	return out
+}
+
+// Flags are the command-line options shared by the report commands.
+type Flags struct {
+	since, until     string
+	restaurant, item string
+	status           string
+	min, max         string
+	sort             string
+	reverse          bool
+}
+
+// Register adds the filter flags to fs. Sorting flags are only added when
+// sorting is true, for commands whose output has its own order.
+func Register(fs *flag.FlagSet, sorting bool) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.since, "since", "", "Only orders on or after this date (2024-01-15, 2024-01, 30d, last-month)")
+	fs.StringVar(&f.until, "until", "", "Only orders up to and including this date")
+	fs.StringVar(&f.restaurant, "restaurant", "", "Restaurant name substring, or /regexp/")
+	fs.StringVar(&f.item, "item", "", "Item name substring, or /regexp/")
+	fs.StringVar(&f.status, "status", "", "Order status, e.g. Delivered")
+	fs.StringVar(&f.min, "min", "", "Minimum order total")
+	fs.StringVar(&f.max, "max", "", "Maximum order total")
+	if sorting {
+		fs.StringVar(&f.sort, "sort", SortDate, "Sort by: date, total, restaurant")
+		fs.BoolVar(&f.reverse, "reverse", false, "Reverse the sort order")
+	}
+	return f
+}
+
+// Options converts the parsed flags. Relative dates are resolved against
+// now, and calendar dates in now's location. Amounts without a currency
+// marker are read in currency, or zomato.DefaultCurrency when it is empty.
+func (f *Flags) Options(now time.Time, currency string) (Options, error) {
+	var opts Options
+	var err error
+	if f.since != "" {
+		if opts.Since, _, err = ParseDate(f.since, now); err != nil {
+			return opts, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if f.until != "" {
+		if _, opts.Until, err = ParseDate(f.until, now); err != nil {
+			return opts, fmt.Errorf("--until: %w", err)
+		}
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return opts, errors.New("--since must be before --until")
+	}
+	if opts.Restaurant, err = NewMatcher(f.restaurant); err != nil {
+		return opts, fmt.Errorf("--restaurant: %w", err)
+	}
+	if opts.Item, err = NewMatcher(f.item); err != nil {
+		return opts, fmt.Errorf("--item: %w", err)
+	}
+	opts.Status = strings.TrimSpace(f.status)
+	if opts.Min, err = parseAmount(f.min, currency); err != nil {
+		return opts, fmt.Errorf("--min: %w", err)
+	}
+	if opts.Max, err = parseAmount(f.max, currency); err != nil {
+		return opts, fmt.Errorf("--max: %w", err)
+	}
+	if opts.Min != nil && opts.Max != nil && opts.Min.Currency != opts.Max.Currency {
+		return opts, errors.New("--min and --max must be in the same currency")
+	}
+	switch key := strings.ToLower(strings.TrimSpace(f.sort)); key {
+	case "", SortDate, SortTotal, SortRestaurant:
+		opts.Sort = key
+	default:
+		return opts, errors.New("--sort must be one of: date, total, restaurant")
+	}
+	opts.Reverse = f.reverse
+	return opts, nil
+}
+
+func parseAmount(value, currency string) (*zomato.Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	money, err := zomato.ParseMoney(value, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &money, nil
+}
@@ -0,0 +1,191 @@
+package filter
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestParseDate(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	now := time.Date(2025, 3, 12, 15, 0, 0, 0, ist) // a Wednesday
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, ist) }
+
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{"2024-01-15", day(2024, 1, 15), day(2024, 1, 16)},
+		{"2024-02", day(2024, 2, 1), day(2024, 3, 1)},
+		{"2023", day(2023, 1, 1), day(2024, 1, 1)},
+		{"30d", now.AddDate(0, 0, -30), now.AddDate(0, 0, -30)},
+		{"2w", now.AddDate(0, 0, -14), now.AddDate(0, 0, -14)},
+		{"today", day(2025, 3, 12), day(2025, 3, 13)},
+		{"yesterday", day(2025, 3, 11), day(2025, 3, 12)},
+		{"this-week", day(2025, 3, 10), day(2025, 3, 17)},
+		{"last-week", day(2025, 3, 3), day(2025, 3, 10)},
+		{"last-month", day(2025, 2, 1), day(2025, 3, 1)},
+		{"Last-Year", day(2024, 1, 1), day(2025, 1, 1)},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseDate(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("ParseDate(%q) = [%v, %v), want [%v, %v)", tt.input, start, end, tt.start, tt.end)
+		}
+	}
+
+	for _, input := range []string{"", "soon", "2024-13", "5x"} {
+		if _, _, err := ParseDate(input, now); err == nil {
+			t.Errorf("ParseDate(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestOptionsApply(t *testing.T) {
+	at := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	inr := func(major int64) zomato.Money { return zomato.Money{Amount: major * 100, Currency: "INR"} }
+	orders := []zomato.Order{
+		{ID: "1", Restaurant: "Pizza Hut", Status: "Delivered", PlacedAt: at(1), Total: inr(500),
+			Items: []zomato.OrderItem{{Name: "Margherita"}}},
+		{ID: "2", Restaurant: "Dominos", Status: "Cancelled", PlacedAt: at(2), Total: inr(300),
+			Items: []zomato.OrderItem{{Name: "Farmhouse"}}},
+		{ID: "3", Restaurant: "Biryani Blues", Status: "Delivered", PlacedAt: at(3), Total: inr(800),
+			Items: []zomato.OrderItem{{Name: "Chicken Biryani"}, {Name: "Raita"}}},
+	}
+	ids := func(orders []zomato.Order) string {
+		s := ""
+		for _, o := range orders {
+			s += o.ID
+		}
+		return s
+	}
+	parse := func(args ...string) Options {
+		t.Helper()
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := Register(fs, true)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		opts, err := f.Options(at(10), "")
+		if err != nil {
+			t.Fatalf("Options(%v) failed: %v", args, err)
+		}
+		return opts
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "321"},
+		{[]string{"--restaurant", "PIZZA"}, "1"},
+		{[]string{"--restaurant", "/^(pizza|dom)/"}, "21"},
+		{[]string{"--item", "raita"}, "3"},
+		{[]string{"--status", "delivered"}, "31"},
+		{[]string{"--min", "400", "--max", "₹600"}, "1"},
+		{[]string{"--since", "2024-01-02", "--until", "2024-01-02"}, "2"},
+		{[]string{"--sort", "total"}, "312"},
+		{[]string{"--sort", "restaurant", "--reverse"}, "123"},
+	}
+	for _, tt := range tests {
+		if got := ids(parse(tt.args...).Apply(orders)); got != tt.want {
+			t.Errorf("Apply with %v = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+
+	limited := parse("--sort", "total")
+	limited.Limit = 1
+	if got := ids(limited.Apply(orders)); got != "3" {
+		t.Errorf("Apply with limit 1 = %s, want 3", got)
+	}
+	if orders[0].ID != "1" {
+		t.Error("Apply reordered its input")
+	}
+}
+
+func TestOptionsApplyCurrencies(t *testing.T) {
+	money := func(major int64, currency string) zomato.Money {
+		return zomato.Money{Amount: major * 100, Currency: currency}
+	}
+	orders := []zomato.Order{
+		{ID: "1", Total: money(500, "INR")},
+		{ID: "2", Total: money(500, "AED")},
+		{ID: "3", Total: money(40, "AED")},
+		{ID: "4", Total: money(300, "INR")},
+	}
+	tests := []struct {
+		args     []string
+		currency string
+		want     string
+	}{
+		{[]string{"--min", "400"}, "", "1"},
+		{[]string{"--min", "AED 100"}, "", "2"},
+		{[]string{"--min", "100"}, "AED", "2"},
+		{[]string{"--max", "₹400"}, "AED", "4"},
+		{[]string{"--sort", "total"}, "", "2314"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := Register(fs, true)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		opts, err := f.Options(time.Now(), tt.currency)
+		if err != nil {
+			t.Fatalf("Options(%v, %q) failed: %v", tt.args, tt.currency, err)
+		}
+		got := ""
+		for _, o := range opts.Apply(orders) {
+			got += o.ID
+		}
+		if got != tt.want {
+			t.Errorf("Apply with %v in %q = %s, want %s", tt.args, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFlagsOptionsErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--since", "2024-02", "--until", "2024-01"},
+		{"--restaurant", "/(/"},
+		{"--sort", "rating"},
+		{"--min", "lots"},
+		{"--min", "AED 10", "--max", "₹600"},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := Register(fs, true)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Options(time.Now(), ""); err == nil {
+			t.Errorf("Options(%v) succeeded, want error", args)
+		}
+	}
+}
+
+func TestMatcherSubstring(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		ok      bool
+	}{
+		{"Pizza", "pizza", true},
+		{"", "", true},
+		{"/^pizza/", "/^pizza/", false},
+	}
+	for _, tt := range tests {
+		m, err := NewMatcher(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := m.Substring(); ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("Substring(%q) = %q, %v, want %q, %v", tt.pattern, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/maheshrijal/zocli/internal/archive"
+)
+
+func ArchiveTable(w io.Writer, entries []archive.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No archived responses found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FETCHED\tKIND\tKEY\tSIZE\tFILE")
+	fmt.Fprintln(tw, "-------\t----\t---\t----\t----")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d B\t%s\n",
+			formatTime(e.FetchedAt.Local()),
+			e.Kind,
+			e.Key,
+			e.Size,
+			filepath.Base(e.Path),
+		)
+	}
+	tw.Flush()
+}
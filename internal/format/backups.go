@@ -0,0 +1,29 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+)
+
+func BackupsTable(w io.Writer, backups []fileutil.Backup) {
+	if len(backups) == 0 {
+		fmt.Fprintln(w, "No backups found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GENERATION\tSAVED\tSIZE\tPATH")
+	fmt.Fprintln(tw, "----------\t-----\t----\t----")
+	for _, b := range backups {
+		fmt.Fprintf(tw, "%d\t%s\t%d B\t%s\n",
+			b.Generation,
+			formatTime(b.ModTime),
+			b.Size,
+			b.Path,
+		)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,66 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/budget"
+)
+
+// BudgetTable prints each budget's progress through the current month.
+func BudgetTable(w io.Writer, statuses []budget.Status) {
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No budgets set. Add one with 'zocli budget set --monthly AMOUNT'.")
+		return
+	}
+	headers := []string{"Budget", "Orders", "Spent", "Limit", "Used", "Projected", "Status"}
+	rows := make([][]string, 0, len(statuses))
+	for _, s := range statuses {
+		rows = append(rows, []string{
+			s.Name(),
+			fmt.Sprintf("%d", s.Orders),
+			s.Spent.String(),
+			s.Monthly.String(),
+			fmt.Sprintf("%s %3.0f%%", Bar(s.Percent(), 10), s.Percent()),
+			s.Projected.String(),
+			budgetState(s),
+		})
+	}
+	alignRight := []bool{false, true, true, true, false, true, false}
+	writeBoxTable(w, headers, rows, alignRight)
+}
+
+func budgetState(s budget.Status) string {
+	switch {
+	case s.Over():
+		return "over"
+	case !s.OnTrack():
+		return "at risk"
+	default:
+		return "ok"
+	}
+}
+
+// Bar draws percent (clamped to 0–100) as a bar of width cells.
+func Bar(percent float64, width int) string {
+	filled := int(percent/100*float64(width) + 0.5)
+	filled = min(max(filled, 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// BudgetRecords is the "budgets" section.
+func BudgetRecords(statuses []budget.Status) Section {
+	s := Section{
+		Name: "budgets",
+		Columns: []string{"restaurant", "category", "currency", "limit", "orders", "spent",
+			"percent", "projected", "status"},
+	}
+	for _, st := range statuses {
+		s.Rows = append(s.Rows, []any{
+			st.Restaurant, st.Category, currencyOf(st.Monthly), amount(st.Monthly), st.Orders,
+			amount(st.Spent), decimal(st.Percent()), amount(st.Projected), budgetState(st),
+		})
+	}
+	return s
+}
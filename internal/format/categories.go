@@ -0,0 +1,74 @@
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func StatsCategories(w io.Writer, totals []stats.TagTotal) {
+	if len(totals) == 0 {
+		fmt.Fprintln(w, "No orders to categorize.")
+		return
+	}
+	headers := []string{"Tag", "Orders", "Share", "Spend"}
+	rows := make([][]string, 0, len(totals))
+	for _, t := range totals {
+		rows = append(rows, []string{
+			t.Tag,
+			fmt.Sprintf("%d", t.Count),
+			fmt.Sprintf("%.1f%%", t.Percent),
+			t.Total.String(),
+		})
+	}
+	alignRight := []bool{false, true, true, true}
+	writeBoxTable(w, headers, rows, alignRight)
+}
+
+// StatsCategoryTrend prints spend per period for the given tags, one
+// column per tag.
+func StatsCategoryTrend(w io.Writer, periods []stats.TagPeriod, tags []string) {
+	if len(periods) == 0 || len(tags) == 0 {
+		fmt.Fprintln(w, "No periods to display.")
+		return
+	}
+	headers := append([]string{"Period"}, tags...)
+	column := make(map[string]int, len(tags))
+	for i, tag := range tags {
+		column[tag] = i + 1
+	}
+	var rows [][]string
+	index := map[string]int{}
+	currency := ""
+	for _, p := range periods {
+		if currency == "" {
+			currency = p.Total.Currency
+		}
+		i, ok := index[p.Period]
+		if !ok {
+			i = len(rows)
+			index[p.Period] = i
+			row := make([]string, len(headers))
+			row[0] = p.Period
+			rows = append(rows, row)
+		}
+		if c, ok := column[p.Tag]; ok {
+			rows[i][c] = p.Total.String()
+		}
+	}
+	empty := zomato.Money{Currency: currency}.String()
+	for _, row := range rows {
+		for c := 1; c < len(row); c++ {
+			if row[c] == "" {
+				row[c] = empty
+			}
+		}
+	}
+	alignRight := make([]bool, len(headers))
+	for i := 1; i < len(alignRight); i++ {
+		alignRight[i] = true
+	}
+	writeBoxTable(w, headers, rows, alignRight)
+}
@@ -0,0 +1,306 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+)
+
+// DefaultChartWidth is the width charts are drawn to when the terminal
+// width is unknown.
+const DefaultChartWidth = 80
+
+// ChartStyle controls how charts are drawn.
+type ChartStyle struct {
+	Width int  // columns available; 0 means DefaultChartWidth
+	ASCII bool // plain ASCII instead of Unicode block characters
+	Color bool // ANSI colors
+}
+
+func (s ChartStyle) width() int {
+	if s.Width <= 0 {
+		return DefaultChartWidth
+	}
+	return s.Width
+}
+
+// paint wraps text in a 256-color ANSI foreground color when the style
+// has colors.
+func (s ChartStyle) paint(text string, color int) string {
+	if !s.Color || text == "" {
+		return text
+	}
+	return fmt.Sprintf("\x1b[38;5;%dm%s\x1b[0m", color, text)
+}
+
+const (
+	barColor   = 205
+	sparkColor = 42
+)
+
+// heatColors are the heatmap's colors from no orders to the busiest days.
+var heatColors = [5]int{238, 22, 28, 34, 40}
+
+var (
+	heatGlyphs      = [5]string{"·", "░", "▒", "▓", "█"}
+	heatGlyphsASCII = [5]string{".", "-", "+", "*", "#"}
+	sparkGlyphs     = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	sparkGlyphASCII = []string{"_", ".", "-", "~", "=", "*", "#", "@"}
+	barEighths      = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+)
+
+// ChartRow is one bar of a bar chart.
+type ChartRow struct {
+	Label string
+	Value float64
+	Text  string // shown after the bar, e.g. the formatted value
+}
+
+// BarChart draws one horizontal bar per row, scaled so the largest value
+// fills the space left by the labels and texts.
+func BarChart(w io.Writer, rows []ChartRow, style ChartStyle) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No data to display.")
+		return
+	}
+	width := style.width()
+	labelWidth, textWidth := 0, 0
+	largest := 0.0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, runeLen(row.Label))
+		textWidth = max(textWidth, runeLen(row.Text))
+		largest = math.Max(largest, row.Value)
+	}
+	labelWidth = min(labelWidth, max(width/3, 8))
+	barWidth := max(width-labelWidth-textWidth-4, 10)
+
+	for _, row := range rows {
+		cells := 0.0
+		if largest > 0 && row.Value > 0 {
+			cells = row.Value / largest * float64(barWidth)
+		}
+		bar := drawBar(cells, style.ASCII)
+		fmt.Fprintf(w, "%s  %s%s  %s\n",
+			pad(truncate(row.Label, labelWidth, style.ASCII), labelWidth, false),
+			style.paint(bar, barColor),
+			strings.Repeat(" ", barWidth-runeLen(bar)),
+			pad(row.Text, textWidth, true))
+	}
+}
+
+// drawBar draws a bar cells long, in eighths of a cell with Unicode blocks
+// or rounded to whole cells in ASCII.
+func drawBar(cells float64, ascii bool) string {
+	if ascii {
+		return strings.Repeat("#", int(math.Round(cells)))
+	}
+	eighths := int(math.Round(cells * 8))
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
+
+// truncate shortens value to width runes, marking the cut.
+func truncate(value string, width int, ascii bool) string {
+	if runeLen(value) <= width {
+		return value
+	}
+	runes := []rune(value)
+	if ascii {
+		return string(runes[:max(width-3, 0)]) + "..."
+	}
+	return string(runes[:max(width-1, 0)]) + "…"
+}
+
+// Sparkline draws values as a line of bars from zero to the largest value,
+// one character each.
+func Sparkline(values []float64, ascii bool) string {
+	glyphs := sparkGlyphs
+	if ascii {
+		glyphs = sparkGlyphASCII
+	}
+	largest := 0.0
+	for _, v := range values {
+		largest = math.Max(largest, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if largest > 0 && v > 0 {
+			level = int(math.Round(v / largest * float64(len(glyphs)-1)))
+		}
+		b.WriteString(glyphs[level])
+	}
+	return b.String()
+}
+
+// ChartGroups draws the spend of each period as a bar, under a sparkline
+// of the whole range.
+func ChartGroups(w io.Writer, groups []stats.Group, style ChartStyle) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No groups to display.")
+		return
+	}
+	rows := make([]ChartRow, 0, len(groups))
+	values := make([]float64, 0, len(groups))
+	for _, group := range groups {
+		rows = append(rows, ChartRow{
+			Label: group.Key,
+			Value: group.Total.Float(),
+			Text:  fmt.Sprintf("%s  %s", group.Total, plural(group.Count, "order")),
+		})
+		values = append(values, group.Total.Float())
+	}
+	if len(groups) > 1 {
+		// Keep the latest periods when they don't all fit.
+		const label = "Trend  "
+		if room := style.width() - len(label); len(values) > room {
+			values = values[len(values)-max(room, 1):]
+		}
+		fmt.Fprintf(w, "%s%s\n\n", label, style.paint(Sparkline(values, style.ASCII), sparkColor))
+	}
+	BarChart(w, rows, style)
+}
+
+// ChartBuckets draws the order count of each bucket, e.g. weekdays or top
+// restaurants.
+func ChartBuckets(w io.Writer, buckets []stats.Bucket, style ChartStyle) {
+	rows := make([]ChartRow, 0, len(buckets))
+	for _, bucket := range buckets {
+		rows = append(rows, ChartRow{
+			Label: bucket.Key,
+			Value: float64(bucket.Count),
+			Text:  fmt.Sprintf("%d  %5.1f%%", bucket.Count, bucket.Percent),
+		})
+	}
+	BarChart(w, rows, style)
+}
+
+// ChartSpendByWeekday draws the spend of each weekday.
+func ChartSpendByWeekday(w io.Writer, buckets []stats.SpendBucket, style ChartStyle) {
+	rows := make([]ChartRow, 0, len(buckets))
+	for _, bucket := range buckets {
+		rows = append(rows, ChartRow{
+			Label: bucket.Key,
+			Value: bucket.Total.Float(),
+			Text:  fmt.Sprintf("%s  %s", bucket.Total, plural(bucket.Count, "order")),
+		})
+	}
+	BarChart(w, rows, style)
+}
+
+// ChartCategories draws the spend of each tag.
+func ChartCategories(w io.Writer, totals []stats.TagTotal, style ChartStyle) {
+	if len(totals) == 0 {
+		fmt.Fprintln(w, "No orders to tag.")
+		return
+	}
+	rows := make([]ChartRow, 0, len(totals))
+	for _, t := range totals {
+		rows = append(rows, ChartRow{
+			Label: t.Tag,
+			Value: t.Total.Float(),
+			Text:  fmt.Sprintf("%s  %s", t.Total, plural(t.Count, "order")),
+		})
+	}
+	BarChart(w, rows, style)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Heatmap draws orders per day as a calendar: one column per week, Monday
+// to Sunday from top to bottom, shaded by how many orders each day had
+// relative to the busiest day. Weeks that don't fit the width are dropped
+// from the start.
+func Heatmap(w io.Writer, days []stats.DayCount, style ChartStyle) {
+	if len(days) == 0 {
+		fmt.Fprintln(w, "No days to display.")
+		return
+	}
+	glyphs := heatGlyphs
+	if style.ASCII {
+		glyphs = heatGlyphsASCII
+	}
+	busiest := 0
+	for _, d := range days {
+		busiest = max(busiest, d.Count)
+	}
+
+	// Grid cell (week, weekday) holds days[week*7+weekday-offset].
+	offset := (int(days[0].Date.Weekday()) + 6) % 7 // Monday first
+	weeks := (offset + len(days) + 6) / 7
+	const labelWidth = 4
+	cellWidth := 2
+	if labelWidth+weeks*cellWidth > style.width() {
+		cellWidth = 1
+	}
+	// Always keep the latest week, however narrow the terminal.
+	first := max(weeks-max((style.width()-labelWidth)/cellWidth, 1), 0)
+	dayAt := func(week, weekday int) (stats.DayCount, bool) {
+		i := week*7 + weekday - offset
+		if i < 0 || i >= len(days) {
+			return stats.DayCount{}, false
+		}
+		return days[i], true
+	}
+
+	// Month names over the week holding each month's first day.
+	header := []rune(strings.Repeat(" ", (weeks-first)*cellWidth))
+	next := 0
+	for week := first; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			d, ok := dayAt(week, weekday)
+			col := (week - first) * cellWidth
+			if !ok || d.Date.Day() != 1 || col < next || col+3 > len(header) {
+				continue
+			}
+			copy(header[col:], []rune(d.Date.Format("Jan")))
+			next = col + 4
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(strings.Repeat(" ", labelWidth)+string(header), " "))
+
+	for weekday := 0; weekday < 7; weekday++ {
+		var b strings.Builder
+		label := ""
+		if weekday%2 == 0 && weekday < 6 {
+			label = time.Weekday((weekday + 1) % 7).String()[:3]
+		}
+		b.WriteString(pad(label, labelWidth, false))
+		for week := first; week < weeks; week++ {
+			d, ok := dayAt(week, weekday)
+			if !ok {
+				b.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			}
+			level := heatLevel(d.Count, busiest)
+			b.WriteString(style.paint(glyphs[level], heatColors[level]))
+			if cellWidth > 1 {
+				b.WriteString(" ")
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+
+	legend := make([]string, len(glyphs))
+	for level, glyph := range glyphs {
+		legend[level] = style.paint(glyph, heatColors[level])
+	}
+	fmt.Fprintf(w, "\n%sLess %s More\n", strings.Repeat(" ", labelWidth), strings.Join(legend, " "))
+}
+
+// heatLevel buckets count into 0 (no orders) to 4 (over three quarters
+// of the busiest day).
+func heatLevel(count, busiest int) int {
+	if count <= 0 || busiest <= 0 {
+		return 0
+	}
+	return min(max((count*4+busiest-1)/busiest, 1), 4)
+}
@@ -0,0 +1,135 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		ascii  bool
+		want   string
+	}{
+		{[]float64{0, 1, 2, 4, 8}, false, "▁▂▃▅█"},
+		{[]float64{0, 1, 2, 4, 8}, true, "_.-=@"},
+		{[]float64{5, 5}, false, "██"},
+		{[]float64{0, 0}, false, "▁▁"},
+		{nil, false, ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values, tt.ascii); got != tt.want {
+			t.Errorf("Sparkline(%v, %v) = %q, want %q", tt.values, tt.ascii, got, tt.want)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	rows := []ChartRow{
+		{Label: "Monday", Value: 10, Text: "10"},
+		{Label: "Tuesday", Value: 5, Text: "5"},
+		{Label: "Wednesday", Value: 0, Text: "0"},
+	}
+	buf := new(bytes.Buffer)
+	BarChart(buf, rows, ChartStyle{Width: 40, ASCII: true})
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("BarChart wrote %d lines, want 3:\n%s", len(lines), buf)
+	}
+	for i, line := range lines {
+		if runeLen(line) != 40 {
+			t.Errorf("line %d is %d wide, want 40: %q", i, runeLen(line), line)
+		}
+	}
+	// 40 columns less the 9-wide labels, 2-wide texts and gaps.
+	if got := strings.Count(lines[0], "#"); got != 25 {
+		t.Errorf("largest bar = %d cells, want 25", got)
+	}
+	if got := strings.Count(lines[1], "#"); got != 13 {
+		t.Errorf("half bar = %d cells, want 13", got)
+	}
+	if strings.Contains(lines[2], "#") {
+		t.Errorf("zero bar drawn: %q", lines[2])
+	}
+
+	buf.Reset()
+	BarChart(buf, rows[:1], ChartStyle{Width: 40, Color: true})
+	if !strings.Contains(buf.String(), "\x1b[38;5;205m█") {
+		t.Errorf("colored bar missing: %q", buf)
+	}
+}
+
+func TestBarChartTruncatesLabels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rows := []ChartRow{{Label: "A very long restaurant name indeed", Value: 1, Text: "1"}}
+	BarChart(buf, rows, ChartStyle{Width: 30})
+	if !strings.HasPrefix(buf.String(), "A very lo…  ") {
+		t.Errorf("BarChart = %q, want the label cut to 10 columns", buf)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	// Wednesday 2024-05-01 to Sunday 2024-05-12.
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var days []stats.DayCount
+	for i := 0; i < 12; i++ {
+		days = append(days, stats.DayCount{Date: start.AddDate(0, 0, i)})
+	}
+	days[0].Count = 4  // Wed
+	days[1].Count = 1  // Thu
+	days[11].Count = 2 // Sun
+
+	buf := new(bytes.Buffer)
+	Heatmap(buf, days, ChartStyle{Width: 80, ASCII: true})
+	want := strings.Join([]string{
+		"    May",
+		"Mon   .",
+		"      .",
+		"Wed # .",
+		"    - .",
+		"Fri . .",
+		"    . .",
+		"    . +",
+		"",
+		"    Less . - + * # More",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("Heatmap =\n%s\nwant\n%s", buf, want)
+	}
+
+	// Too narrow for both weeks: the first is dropped.
+	buf.Reset()
+	Heatmap(buf, days, ChartStyle{Width: 5, ASCII: true})
+	if lines := strings.Split(buf.String(), "\n"); lines[3] != "Wed ." || lines[7] != "    +" {
+		t.Errorf("narrow Heatmap =\n%s", buf)
+	}
+
+	// Narrower than the labels: only the last week is drawn.
+	buf.Reset()
+	Heatmap(buf, days, ChartStyle{Width: 3, ASCII: true})
+	if lines := strings.Split(buf.String(), "\n"); lines[3] != "Wed ." || lines[7] != "    +" {
+		t.Errorf("Heatmap narrower than its labels =\n%s", buf)
+	}
+}
+
+func TestHeatLevel(t *testing.T) {
+	tests := []struct{ count, busiest, want int }{
+		{0, 4, 0},
+		{1, 4, 1},
+		{2, 4, 2},
+		{3, 4, 3},
+		{4, 4, 4},
+		{1, 100, 1},
+		{1, 1, 4},
+	}
+	for _, tt := range tests {
+		if got := heatLevel(tt.count, tt.busiest); got != tt.want {
+			t.Errorf("heatLevel(%d, %d) = %d, want %d", tt.count, tt.busiest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+)
+
+func StatsForecast(w io.Writer, f stats.Forecast) {
+	headers := []string{"Month", "Orders", "Spend", "Likely Range", "Based On"}
+	basis := fmt.Sprintf("%d months", f.History)
+	if f.Seasonal {
+		basis += ", seasonal"
+	}
+	row := []string{
+		f.Month.Format("Jan 2006"),
+		fmt.Sprintf("%.1f", f.Orders),
+		f.Spend.String(),
+		fmt.Sprintf("%s – %s", f.Low, f.High),
+		basis,
+	}
+	alignRight := []bool{false, true, true, true, false}
+	writeBoxTable(w, headers, [][]string{row}, alignRight)
+}
+
+func StatsAnomalies(w io.Writer, anomalies []stats.Anomaly) {
+	if len(anomalies) == 0 {
+		fmt.Fprintln(w, "No unusual months, weeks or orders.")
+		return
+	}
+	headers := []string{"Kind", "When", "Detail", "Spend", "Typical", "Score"}
+	rows := make([][]string, 0, len(anomalies))
+	for _, a := range anomalies {
+		when, detail := a.Key, fmt.Sprintf("%d orders", a.Orders)
+		if a.Kind == stats.AnomalyOrder {
+			when = formatDate(a.Start)
+			detail = fmt.Sprintf("%s (%s)", a.Restaurant, a.Key)
+		}
+		rows = append(rows, []string{
+			a.Kind,
+			when,
+			detail,
+			a.Total.String(),
+			a.Typical.String(),
+			fmt.Sprintf("%+.1f", a.Score),
+		})
+	}
+	alignRight := []bool{false, false, false, true, true, true}
+	writeBoxTable(w, headers, rows, alignRight)
+}
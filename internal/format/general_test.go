@@ -15,9 +15,9 @@ func TestStatsTable(t *testing.T) {
 	
 	summary := stats.Summary{
 		Count:    10,
-		Total:    1000.50,
-		Average:  100.05,
-		Currency: "₹",
+		Total:    zomato.Money{Amount: 100050, Currency: "INR"},
+		Average:  zomato.Money{Amount: 10005, Currency: "INR"},
+		Currency: "INR",
 		Earliest: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
 		Latest:   time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
 	}
@@ -45,7 +45,7 @@ func TestOrdersTable(t *testing.T) {
 			Restaurant: "Test Rest",
 			Status:     "Delivered",
 			PlacedAt:   time.Date(2023, 5, 20, 14, 30, 0, 0, time.Local),
-			Total:      "₹500",
+			Total:      zomato.Money{Amount: 50000, Currency: "INR"},
 			Items:      []zomato.OrderItem{{Name: "Food", Quantity: 1}},
 		},
 	}
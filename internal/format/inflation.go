@@ -6,25 +6,38 @@ import (
 	"text/tabwriter"
 
 	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
 )
 
 type InflationSummary struct {
 	ItemName    string
+	Orders      int
 	FirstSeen   string
 	FirstPrice  float64
 	LastPrice   float64
+	Currency    string
 	TotalChange float64
+	Confidence  float64
+}
+
+func formatPrice(value float64, currency string) string {
+	return zomato.MoneyFromFloat(value, currency).String()
+}
+
+// formatConfidence shows a price estimate's confidence as a percentage.
+func formatConfidence(c float64) string {
+	return fmt.Sprintf("%.0f%%", c*100)
 }
 
 func InflationTable(w io.Writer, points []stats.ItemPricePoint) {
 	if len(points) == 0 {
-		fmt.Fprintln(w, "No matching orders found (or no single-item orders for accurate pricing).")
+		fmt.Fprintln(w, "No matching orders found (or not enough orders to price the item).")
 		return
 	}
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "DATE\tRESTAURANT\tITEM\tUNIT PRICE\tCHANGE %")
-	fmt.Fprintln(tw, "----\t----------\t----\t----------\t--------")
+	fmt.Fprintln(tw, "DATE\tRESTAURANT\tITEM\tUNIT PRICE\tCHANGE %\tCONFIDENCE")
+	fmt.Fprintln(tw, "----\t----------\t----\t----------\t--------\t----------")
 
 	for _, p := range points {
 		changeStr := "-"
@@ -42,12 +55,13 @@ func InflationTable(w io.Writer, points []stats.ItemPricePoint) {
 			item = item[:27] + "..."
 		}
 
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\t%s\n",
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
 			p.Date.Format("2006-01-02"),
 			p.Restaurant,
 			item,
-			p.UnitPrice,
+			formatPrice(p.UnitPrice, p.Currency),
 			changeStr,
+			formatConfidence(p.Confidence),
 		)
 	}
 	tw.Flush()
@@ -60,8 +74,8 @@ func InflationSummaryTable(w io.Writer, summaries []InflationSummary) {
 	}
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "ITEM\tFIRST SEEN\tFIRST PRICE\tLAST PRICE\tCHANGE")
-	fmt.Fprintln(tw, "----\t----------\t-----------\t----------\t------")
+	fmt.Fprintln(tw, "ITEM\tORDERS\tFIRST SEEN\tFIRST PRICE\tLAST PRICE\tCHANGE\tCONFIDENCE")
+	fmt.Fprintln(tw, "----\t------\t----------\t-----------\t----------\t------\t----------")
 
 	for _, s := range summaries {
 		changeStr := "0%"
@@ -71,13 +85,44 @@ func InflationSummaryTable(w io.Writer, summaries []InflationSummary) {
 			changeStr = fmt.Sprintf("%.1f%% 🔻", s.TotalChange)
 		}
 
-		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%.2f\t%s\n",
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
 			s.ItemName,
+			s.Orders,
 			s.FirstSeen,
-			s.FirstPrice,
-			s.LastPrice,
+			formatPrice(s.FirstPrice, s.Currency),
+			formatPrice(s.LastPrice, s.Currency),
 			changeStr,
+			formatConfidence(s.Confidence),
 		)
 	}
 	tw.Flush()
 }
+
+// InflationIndexTable prints the personal inflation index, with the
+// reference CPI when withCPI is set.
+func InflationIndexTable(w io.Writer, points []stats.IndexPoint, withCPI bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if withCPI {
+		fmt.Fprintln(tw, "PERIOD\tINDEX\tCHANGE\tITEMS\tCPI")
+		fmt.Fprintln(tw, "------\t-----\t------\t-----\t---")
+	} else {
+		fmt.Fprintln(tw, "PERIOD\tINDEX\tCHANGE\tITEMS")
+		fmt.Fprintln(tw, "------\t-----\t------\t-----")
+	}
+	for i, p := range points {
+		changeStr := "-"
+		if i > 0 && p.Items > 0 {
+			changeStr = fmt.Sprintf("%+.1f%%", p.Change)
+		}
+		fmt.Fprintf(tw, "%s\t%.1f\t%s\t%d", p.Label, p.Index, changeStr, p.Items)
+		if withCPI {
+			cpi := "-"
+			if p.CPI > 0 {
+				cpi = fmt.Sprintf("%.1f", p.CPI)
+			}
+			fmt.Fprintf(tw, "\t%s", cpi)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
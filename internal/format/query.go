@@ -0,0 +1,41 @@
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/maheshrijal/zocli/internal/query"
+)
+
+// QueryTable prints a query result, right-aligning numeric columns.
+func QueryTable(w io.Writer, res *query.Result) {
+	if len(res.Rows) == 0 {
+		fmt.Fprintln(w, "No matching orders.")
+		return
+	}
+	alignRight := make([]bool, len(res.Columns))
+	rows := make([][]string, len(res.Rows))
+	for i, row := range res.Rows {
+		rows[i] = make([]string, len(row))
+		for j, v := range row {
+			rows[i][j] = v.String()
+			if v.Kind == query.KindNumber || v.Kind == query.KindMoney {
+				alignRight[j] = true
+			}
+		}
+	}
+	writeBoxTable(w, res.Columns, rows, alignRight)
+}
+
+// QueryRecords is the "query" section.
+func QueryRecords(res *query.Result) Section {
+	s := Section{Name: "query", Columns: res.Columns}
+	for _, row := range res.Rows {
+		values := make([]any, len(row))
+		for i, v := range row {
+			values[i] = v.Raw()
+		}
+		s.Rows = append(s.Rows, values)
+	}
+	return s
+}
@@ -0,0 +1,414 @@
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Output formats accepted by the global --output option.
+const (
+	OutputTable  = "table"
+	OutputJSON   = "json"
+	OutputNDJSON = "ndjson"
+	OutputCSV    = "csv"
+	OutputTSV    = "tsv"
+)
+
+// ParseOutput validates an --output value.
+func ParseOutput(value string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "", OutputTable:
+		return OutputTable, nil
+	case OutputJSON, OutputNDJSON, OutputCSV, OutputTSV:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (use table, json, ndjson, csv or tsv)", value)
+	}
+}
+
+// Section is one named table of a machine-readable report. Column names
+// are the stable schema; values are strings, ints, json.Number amounts,
+// time.Time or []string.
+type Section struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// WriteSections writes a report in a machine-readable output format.
+//
+// A single section is written as a JSON array of objects, NDJSON objects
+// or one CSV/TSV table. Several sections become a JSON object keyed by
+// section name, NDJSON objects with a "section" field, or CSV/TSV tables
+// separated by a blank line.
+func WriteSections(w io.Writer, output string, sections ...Section) error {
+	switch output {
+	case OutputJSON:
+		var doc any
+		if len(sections) == 1 {
+			doc = sectionObjects(sections[0], false)
+		} else {
+			byName := make(map[string]any, len(sections))
+			for _, s := range sections {
+				byName[s.Name] = sectionObjects(s, false)
+			}
+			doc = byName
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case OutputNDJSON:
+		enc := json.NewEncoder(w)
+		for _, s := range sections {
+			for _, obj := range sectionObjects(s, len(sections) > 1) {
+				if err := enc.Encode(obj); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case OutputCSV, OutputTSV:
+		for i, s := range sections {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			cw := csv.NewWriter(w)
+			if output == OutputTSV {
+				cw.Comma = '\t'
+			}
+			if err := cw.Write(s.Columns); err != nil {
+				return err
+			}
+			for _, row := range s.Rows {
+				record := make([]string, len(row))
+				for j, v := range row {
+					record[j] = textValue(v)
+				}
+				if err := cw.Write(record); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("output format %q has no machine-readable form", output)
+	}
+}
+
+// orderedObject keeps the column order when encoded as JSON.
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(jsonValue(o.values[i]))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+func sectionObjects(s Section, withSection bool) []orderedObject {
+	out := make([]orderedObject, 0, len(s.Rows))
+	for _, row := range s.Rows {
+		obj := orderedObject{}
+		if withSection {
+			obj.keys = append(obj.keys, "section")
+			obj.values = append(obj.values, s.Name)
+		}
+		obj.keys = append(obj.keys, s.Columns...)
+		obj.values = append(obj.values, row...)
+		out = append(out, obj)
+	}
+	return out
+}
+
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		if v == nil {
+			return []string{}
+		}
+	}
+	return v
+}
+
+func textValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case json.Number:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, "; ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// amount renders money as an exact decimal number in major units.
+func amount(m zomato.Money) json.Number {
+	return json.Number(m.Number())
+}
+
+// decimal renders a ratio or float price with two decimals.
+func decimal(v float64) json.Number {
+	return json.Number(strconv.FormatFloat(v, 'f', 2, 64))
+}
+
+func currencyOf(m zomato.Money) string {
+	if m.Currency == "" {
+		return zomato.DefaultCurrency
+	}
+	return m.Currency
+}
+
+// OrderRecords is the "orders" section.
+func OrderRecords(orders []zomato.Order) Section {
+	s := Section{
+		Name:    "orders",
+		Columns: []string{"id", "restaurant", "status", "placed_at", "total", "currency", "items"},
+	}
+	for _, order := range orders {
+		items := make([]string, 0, len(order.Items))
+		for _, item := range order.Items {
+			items = append(items, fmt.Sprintf("%dx %s", max(item.Quantity, 1), item.Name))
+		}
+		s.Rows = append(s.Rows, []any{
+			order.ID, order.Restaurant, order.Status, order.PlacedAt,
+			amount(order.Total), currencyOf(order.Total), items,
+		})
+	}
+	return s
+}
+
+// SummaryRecords is the "summary" section, one row per currency.
+func SummaryRecords(summaries []stats.Summary) Section {
+	s := Section{
+		Name:    "summary",
+		Columns: []string{"currency", "orders", "total", "average", "earliest", "latest"},
+	}
+	for _, summary := range summaries {
+		s.Rows = append(s.Rows, []any{
+			currencyOf(summary.Total), summary.Count, amount(summary.Total), amount(summary.Average),
+			summary.Earliest, summary.Latest,
+		})
+	}
+	return s
+}
+
+// GroupRecords is the "groups" section.
+func GroupRecords(groups []stats.Group) Section {
+	s := Section{
+		Name:    "groups",
+		Columns: []string{"period", "currency", "orders", "total", "average"},
+	}
+	for _, group := range groups {
+		s.Rows = append(s.Rows, []any{
+			group.Key, currencyOf(group.Total), group.Count, amount(group.Total), amount(group.Average),
+		})
+	}
+	return s
+}
+
+// SpendRecords is the "spend_by_weekday" section.
+func SpendRecords(buckets []stats.SpendBucket) Section {
+	s := Section{
+		Name:    "spend_by_weekday",
+		Columns: []string{"weekday", "currency", "orders", "total", "average"},
+	}
+	for _, bucket := range buckets {
+		s.Rows = append(s.Rows, []any{
+			bucket.Key, currencyOf(bucket.Total), bucket.Count, amount(bucket.Total), amount(bucket.Average),
+		})
+	}
+	return s
+}
+
+// BucketRecords is a count-and-share section such as "weekdays" or
+// "top_items"; key names the first column.
+func BucketRecords(name, key, count string, buckets []stats.Bucket) Section {
+	s := Section{
+		Name:    name,
+		Columns: []string{key, count, "percent"},
+	}
+	for _, bucket := range buckets {
+		s.Rows = append(s.Rows, []any{bucket.Key, bucket.Count, decimal(bucket.Percent)})
+	}
+	return s
+}
+
+// PricePointRecords is the "price_history" section.
+func PricePointRecords(points []stats.ItemPricePoint) Section {
+	s := Section{
+		Name: "price_history",
+		Columns: []string{"date", "order_id", "restaurant", "item", "quantity",
+			"unit_price", "currency", "order_total", "change_percent", "confidence", "pre_tax"},
+	}
+	for _, p := range points {
+		s.Rows = append(s.Rows, []any{
+			p.Date, p.OrderId, p.Restaurant, p.ItemName, p.Quantity,
+			decimal(p.UnitPrice), currencyOf(zomato.Money{Currency: p.Currency}), amount(p.OrderTotal), decimal(p.Change),
+			decimal(p.Confidence), p.PreTax,
+		})
+	}
+	return s
+}
+
+// TrendRecords is the "trends" section.
+func TrendRecords(trends []stats.InflationTrend) Section {
+	s := Section{
+		Name: "trends",
+		Columns: []string{"restaurant", "item", "first_seen", "first_price", "last_price",
+			"currency", "change_percent", "orders", "confidence", "pre_tax"},
+	}
+	for _, t := range trends {
+		s.Rows = append(s.Rows, []any{
+			t.Restaurant, t.ItemName, t.FirstSeen, decimal(t.FirstPrice), decimal(t.LastPrice),
+			currencyOf(zomato.Money{Currency: t.Currency}), decimal(t.TotalChange), t.Count, decimal(t.Confidence),
+			t.PreTax,
+		})
+	}
+	return s
+}
+
+// ForecastRecords is the "forecast" section, one row per currency.
+func ForecastRecords(forecasts []stats.Forecast) Section {
+	s := Section{
+		Name:    "forecast",
+		Columns: []string{"month", "currency", "orders", "spend", "low", "high", "history_months", "seasonal"},
+	}
+	for _, f := range forecasts {
+		s.Rows = append(s.Rows, []any{
+			f.Month.Format("2006-01"), currencyOf(f.Spend), decimal(f.Orders), amount(f.Spend),
+			amount(f.Low), amount(f.High), f.History, f.Seasonal,
+		})
+	}
+	return s
+}
+
+// AnomalyRecords is the "anomalies" section.
+func AnomalyRecords(anomalies []stats.Anomaly) Section {
+	s := Section{
+		Name:    "anomalies",
+		Columns: []string{"kind", "key", "start", "restaurant", "orders", "total", "typical", "currency", "score"},
+	}
+	for _, a := range anomalies {
+		s.Rows = append(s.Rows, []any{
+			a.Kind, a.Key, a.Start, a.Restaurant, a.Orders, amount(a.Total), amount(a.Typical),
+			currencyOf(a.Total), decimal(a.Score),
+		})
+	}
+	return s
+}
+
+// CategoryRecords is the "categories" section.
+func CategoryRecords(totals []stats.TagTotal) Section {
+	s := Section{
+		Name:    "categories",
+		Columns: []string{"tag", "currency", "orders", "percent", "total"},
+	}
+	for _, t := range totals {
+		s.Rows = append(s.Rows, []any{t.Tag, currencyOf(t.Total), t.Count, decimal(t.Percent), amount(t.Total)})
+	}
+	return s
+}
+
+// CategoryPeriodRecords is the "categories_by_period" section.
+func CategoryPeriodRecords(periods []stats.TagPeriod) Section {
+	s := Section{
+		Name:    "categories_by_period",
+		Columns: []string{"period", "tag", "currency", "orders", "total"},
+	}
+	for _, p := range periods {
+		s.Rows = append(s.Rows, []any{p.Period, p.Tag, currencyOf(p.Total), p.Count, amount(p.Total)})
+	}
+	return s
+}
+
+// SuggestionRecords is the "suggestion" section, best pick first.
+func SuggestionRecords(recs []stats.Recommendation) Section {
+	s := Section{
+		Name:    "suggestion",
+		Columns: []string{"restaurant", "item", "rank", "orders", "last_ordered", "typical", "currency", "score", "reasons"},
+	}
+	for i, r := range recs {
+		s.Rows = append(s.Rows, []any{
+			r.Restaurant, r.Dish, i + 1, r.Orders, r.LastOrdered.Format("2006-01-02"),
+			amount(r.Typical), currencyOf(r.Typical), decimal(r.Score), strings.Join(r.Reasons, "; "),
+		})
+	}
+	return s
+}
+
+// IndexRecords is the "inflation_index" section; cpi is empty when unknown.
+func IndexRecords(points []stats.IndexPoint) Section {
+	s := Section{
+		Name:    "inflation_index",
+		Columns: []string{"period", "currency", "index", "change_percent", "items", "cpi"},
+	}
+	for _, p := range points {
+		var cpi any
+		if p.CPI > 0 {
+			cpi = decimal(p.CPI)
+		}
+		s.Rows = append(s.Rows, []any{
+			p.Label, currencyOf(zomato.Money{Currency: p.Currency}), decimal(p.Index), decimal(p.Change), p.Items, cpi,
+		})
+	}
+	return s
+}
+
+// DayRecords is the "orders_per_day" section.
+func DayRecords(days []stats.DayCount) Section {
+	s := Section{
+		Name:    "orders_per_day",
+		Columns: []string{"date", "orders"},
+	}
+	for _, d := range days {
+		s.Rows = append(s.Rows, []any{d.Date.Format("2006-01-02"), d.Count})
+	}
+	return s
+}
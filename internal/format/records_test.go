@@ -0,0 +1,79 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestWriteSections(t *testing.T) {
+	summary := SummaryRecords([]stats.Summary{{
+		Count:    2,
+		Total:    zomato.Money{Amount: 100050, Currency: "INR"},
+		Average:  zomato.Money{Amount: 50025, Currency: "INR"},
+		Earliest: time.Date(2023, 1, 1, 20, 0, 0, 0, time.UTC),
+	}})
+
+	buf := new(bytes.Buffer)
+	if err := WriteSections(buf, OutputJSON, summary); err != nil {
+		t.Fatal(err)
+	}
+	want := `"currency": "INR",
+    "orders": 2,
+    "total": 1000.50,
+    "average": 500.25,
+    "earliest": "2023-01-01T20:00:00Z",
+    "latest": null`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("JSON = %s, want columns in order with exact amounts", buf.String())
+	}
+
+	buf.Reset()
+	groups := GroupRecords([]stats.Group{{Key: "Jan 2023", Count: 2, Total: zomato.Money{Amount: 100050, Currency: "INR"}}})
+	if err := WriteSections(buf, OutputNDJSON, groups, summary); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("NDJSON lines = %d, want 2", len(lines))
+	}
+	var row map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+		t.Fatalf("NDJSON line is not JSON: %v", err)
+	}
+	if row["section"] != "groups" || row["period"] != "Jan 2023" {
+		t.Errorf("NDJSON row = %v, want a groups row tagged with its section", row)
+	}
+
+	buf.Reset()
+	orders := OrderRecords([]zomato.Order{{
+		ID:         "1",
+		Restaurant: "Cafe, Tea",
+		Total:      zomato.Money{Amount: 4000, Currency: "AED"},
+		Items:      []zomato.OrderItem{{Name: "Tea", Quantity: 2}, {Name: "Bun"}},
+	}})
+	if err := WriteSections(buf, OutputTSV, orders); err != nil {
+		t.Fatal(err)
+	}
+	wantTSV := "id\trestaurant\tstatus\tplaced_at\ttotal\tcurrency\titems\n1\tCafe, Tea\t\t\t40.00\tAED\t2x Tea; 1x Bun\n"
+	if buf.String() != wantTSV {
+		t.Errorf("TSV = %q, want %q", buf.String(), wantTSV)
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	if got, err := ParseOutput(""); err != nil || got != OutputTable {
+		t.Errorf("ParseOutput(\"\") = %q, %v, want table", got, err)
+	}
+	if got, err := ParseOutput("NDJSON"); err != nil || got != OutputNDJSON {
+		t.Errorf("ParseOutput(NDJSON) = %q, %v, want ndjson", got, err)
+	}
+	if _, err := ParseOutput("xml"); err == nil {
+		t.Error("ParseOutput(xml) succeeded, want error")
+	}
+}
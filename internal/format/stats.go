@@ -11,11 +11,11 @@ import (
 )
 
 func StatsSummary(w io.Writer, summary stats.Summary) {
-	headers := []string{"Orders", "Total", "Average", "Earliest", "Latest"}
+	headers := []string{"Orders", "Total Spent", "Average Order", "Earliest", "Latest"}
 	row := []string{
 		fmt.Sprintf("%d", summary.Count),
-		formatCurrency(summary.Currency, summary.Total),
-		formatCurrency(summary.Currency, summary.Average),
+		summary.Total.String(),
+		summary.Average.String(),
 		formatDate(summary.Earliest),
 		formatDate(summary.Latest),
 	}
@@ -23,7 +23,7 @@ func StatsSummary(w io.Writer, summary stats.Summary) {
 	writeBoxTable(w, headers, [][]string{row}, alignRight)
 }
 
-func StatsGroups(w io.Writer, groups []stats.Group, currency string) {
+func StatsGroups(w io.Writer, groups []stats.Group) {
 	if len(groups) == 0 {
 		fmt.Fprintln(w, "No groups to display.")
 		return
@@ -34,8 +34,8 @@ func StatsGroups(w io.Writer, groups []stats.Group, currency string) {
 		rows = append(rows, []string{
 			group.Key,
 			fmt.Sprintf("%d", group.Count),
-			formatCurrency(currency, group.Total),
-			formatCurrency(currency, group.Average),
+			group.Total.String(),
+			group.Average.String(),
 		})
 	}
 	alignRight := []bool{false, true, true, true}
@@ -92,15 +92,15 @@ func StatsTopList(w io.Writer, title string, buckets []stats.Bucket) {
 	StatsBuckets(w, headers, rows, alignRight)
 }
 
-func StatsSpendByWeekday(w io.Writer, buckets []stats.SpendBucket, currency string) {
+func StatsSpendByWeekday(w io.Writer, buckets []stats.SpendBucket) {
 	headers := []string{"Day", "Orders", "Total", "Average"}
 	rows := make([][]string, 0, len(buckets))
 	for _, bucket := range buckets {
 		rows = append(rows, []string{
 			bucket.Key,
 			fmt.Sprintf("%d", bucket.Count),
-			formatCurrency(currency, bucket.Total),
-			formatCurrency(currency, bucket.Average),
+			bucket.Total.String(),
+			bucket.Average.String(),
 		})
 	}
 	alignRight := []bool{false, true, true, true}
@@ -176,13 +176,6 @@ func runeLen(value string) int {
 	return utf8.RuneCountInString(value)
 }
 
-func formatCurrency(currency string, value float64) string {
-	if currency == "" {
-		return fmt.Sprintf("%.2f", value)
-	}
-	return fmt.Sprintf("%s%.2f", currency, value)
-}
-
 func formatDate(t time.Time) string {
 	if t.IsZero() {
 		return "-"
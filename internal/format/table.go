@@ -26,7 +26,7 @@ func OrdersTable(w io.Writer, orders []zomato.Order) {
 			order.Restaurant,
 			order.Status,
 			placed,
-			order.Total,
+			order.Total.String(),
 			strings.Join(items, ", "),
 		)
 	}
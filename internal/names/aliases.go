@@ -0,0 +1,91 @@
+package names
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+)
+
+// backupGenerations is how many previous versions of the alias file are
+// kept.
+const backupGenerations = 3
+
+// Aliases map a name variant to the name it should be counted as. Keys
+// match after folding, so one alias covers differences in case and
+// punctuation.
+type Aliases struct {
+	Restaurants map[string]string `json:"restaurants,omitempty"`
+	Items       map[string]string `json:"items,omitempty"`
+}
+
+// LoadAliases reads an alias file. A missing file means no aliases.
+func LoadAliases(path string) (Aliases, error) {
+	var a Aliases
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return a, err
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return a, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return a, nil
+}
+
+// SaveAliases writes the alias file, keeping backups of earlier versions.
+func SaveAliases(path string, a Aliases) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, append(data, '\n'), 0o644, backupGenerations)
+}
+
+// Add maps from to to in m, replacing an alias whose key folds the same.
+// It refuses aliases that fold to themselves or would form a chain.
+func Add(m map[string]string, from, to string) (map[string]string, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return m, errors.New("both names are required")
+	}
+	if Fold(from) == "" || Fold(to) == "" {
+		return m, errors.New("names need at least one letter or digit")
+	}
+	if Fold(from) == Fold(to) {
+		return m, fmt.Errorf("%q and %q are already counted as the same name", from, to)
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	for existing, target := range m {
+		if Fold(existing) == Fold(to) {
+			return m, fmt.Errorf("%q is itself an alias for %q; alias to %q instead", to, target, target)
+		}
+	}
+	m, _ = Remove(m, from)
+	// Re-point aliases that led to from, so nothing chains through it.
+	for existing, target := range m {
+		if Fold(target) == Fold(from) {
+			m[existing] = to
+		}
+	}
+	m[from] = to
+	return m, nil
+}
+
+// Remove deletes the alias whose key folds like from.
+func Remove(m map[string]string, from string) (map[string]string, bool) {
+	for existing := range m {
+		if Fold(existing) == Fold(from) {
+			delete(m, existing)
+			return m, true
+		}
+	}
+	return m, false
+}
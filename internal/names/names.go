@@ -0,0 +1,196 @@
+// Package names folds restaurant and dish name variants together, so
+// "Domino's Pizza", "Dominos Pizza" and "Domino's Pizza (Koramangala)" are
+// counted as one restaurant.
+package names
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Fold reduces a name to the key its variants share: lower case, without
+// apostrophes, punctuation, a trailing "(...)" qualifier or a " - ..."
+// branch suffix, and with "&" read as "and".
+func Fold(name string) string {
+	return fold(stripSuffixes(strings.ToLower(strings.TrimSpace(name))))
+}
+
+// FoldQualified is Fold keeping "(Half)"-style qualifiers and " - 6 pcs"
+// suffixes, which for dishes often name a portion size.
+func FoldQualified(name string) string {
+	return fold(strings.ToLower(strings.TrimSpace(name)))
+}
+
+func fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch {
+		case r == '\'' || r == '’' || r == '`':
+			continue
+		case r == '&':
+			b.WriteString(" and ")
+			space = false
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// stripSuffixes drops trailing "(Full)"-style qualifiers and " - Branch"
+// suffixes, unless that would leave nothing.
+func stripSuffixes(s string) string {
+	for {
+		trimmed := strings.TrimSpace(s)
+		if strings.HasSuffix(trimmed, ")") {
+			if i := strings.LastIndex(trimmed, "("); i > 0 {
+				trimmed = strings.TrimSpace(trimmed[:i])
+			}
+		}
+		if i := strings.LastIndex(trimmed, " - "); i > 0 {
+			trimmed = strings.TrimSpace(trimmed[:i])
+		}
+		if trimmed == s || trimmed == "" {
+			return s
+		}
+		s = trimmed
+	}
+}
+
+// Normalizer rewrites names to one canonical spelling per folded key:
+// the alias target when one is set, otherwise the most common variant.
+type Normalizer struct {
+	aliases Aliases
+	// KeepItemQualifiers folds dish names with FoldQualified, so
+	// "Biryani (Half)" and "Biryani (Full)" stay apart. Price tracking
+	// needs this: merging portion sizes shows up as price changes.
+	KeepItemQualifiers bool
+}
+
+// NewNormalizer returns a Normalizer applying aliases on top of folding.
+func NewNormalizer(aliases Aliases) *Normalizer {
+	return &Normalizer{aliases: aliases}
+}
+
+// Apply returns a copy of orders with restaurant and item names
+// normalized. Other fields, including item slices of untouched orders, are
+// shared with the input.
+func (n *Normalizer) Apply(orders []zomato.Order) []zomato.Order {
+	restaurants := newCanon(n.aliases.Restaurants, Fold)
+	itemFold := Fold
+	if n.KeepItemQualifiers {
+		itemFold = FoldQualified
+	}
+	items := newCanon(n.aliases.Items, itemFold)
+	for _, order := range orders {
+		restaurants.add(order.Restaurant, 1)
+		for _, item := range order.Items {
+			items.add(item.Name, max(item.Quantity, 1))
+		}
+	}
+	restaurants.resolve()
+	items.resolve()
+
+	out := make([]zomato.Order, len(orders))
+	for i, order := range orders {
+		order.Restaurant = restaurants.name(order.Restaurant)
+		if len(order.Items) > 0 {
+			renamed := make([]zomato.OrderItem, len(order.Items))
+			for j, item := range order.Items {
+				item.Name = items.name(item.Name)
+				renamed[j] = item
+			}
+			order.Items = renamed
+		}
+		out[i] = order
+	}
+	return out
+}
+
+// canon picks the display name for each folded key.
+type canon struct {
+	fold     func(string) string
+	aliases  map[string]string // folded name -> alias target
+	variants map[string]map[string]int
+	chosen   map[string]string
+}
+
+func newCanon(aliases map[string]string, fold func(string) string) *canon {
+	c := &canon{
+		fold:     fold,
+		aliases:  make(map[string]string, len(aliases)),
+		variants: map[string]map[string]int{},
+		chosen:   map[string]string{},
+	}
+	for from, to := range aliases {
+		c.aliases[fold(from)] = to
+	}
+	return c
+}
+
+// key is the folded name, following aliases.
+func (c *canon) key(name string) string {
+	key := c.fold(name)
+	if to, ok := c.aliases[key]; ok {
+		return c.fold(to)
+	}
+	return key
+}
+
+func (c *canon) add(name string, weight int) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	key := c.key(name)
+	if c.variants[key] == nil {
+		c.variants[key] = map[string]int{}
+	}
+	c.variants[key][name] += weight
+}
+
+func (c *canon) resolve() {
+	targets := map[string]string{}
+	for _, to := range c.aliases {
+		targets[c.fold(to)] = to
+	}
+	for key, variants := range c.variants {
+		if to, ok := targets[key]; ok {
+			c.chosen[key] = to
+			continue
+		}
+		names := make([]string, 0, len(variants))
+		for name := range variants {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if variants[names[i]] != variants[names[j]] {
+				return variants[names[i]] > variants[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		c.chosen[key] = names[0]
+	}
+}
+
+func (c *canon) name(name string) string {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return name
+	}
+	if chosen, ok := c.chosen[c.key(trimmed)]; ok {
+		return chosen
+	}
+	return trimmed
+}
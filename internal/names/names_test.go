@@ -0,0 +1,162 @@
+package names
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Domino's Pizza", "dominos pizza"},
+		{"  DOMINOS   PIZZA ", "dominos pizza"},
+		{"Domino’s Pizza (Koramangala)", "dominos pizza"},
+		{"Pizza Hut - Indiranagar", "pizza hut"},
+		{"Chicken Biryani (Full) (Serves 2)", "chicken biryani"},
+		{"Burger & Co.", "burger and co"},
+		{"(Combo)", "combo"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.input); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFoldQualified(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Chicken Biryani (Half)", "chicken biryani half"},
+		{"Chicken Biryani (Full)", "chicken biryani full"},
+		{"Momos - 6 Pcs", "momos 6 pcs"},
+		{"Domino's Pizza", "dominos pizza"},
+	}
+	for _, tt := range tests {
+		if got := FoldQualified(tt.input); got != tt.want {
+			t.Errorf("FoldQualified(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizerApply(t *testing.T) {
+	orders := []zomato.Order{
+		{Restaurant: "Domino's Pizza", Items: []zomato.OrderItem{{Name: "Farmhouse", Quantity: 1}}},
+		{Restaurant: "Domino's Pizza", Items: []zomato.OrderItem{{Name: "Chicken Biryani (Full)", Quantity: 2}}},
+		{Restaurant: "Dominos Pizza - HSR", Items: []zomato.OrderItem{{Name: "chicken biryani", Quantity: 1}}},
+		{Restaurant: "Dominoes", Items: []zomato.OrderItem{{Name: "Chkn Biryani", Quantity: 1}}},
+		{Restaurant: "Biryani Blues"},
+	}
+	aliases := Aliases{
+		Restaurants: map[string]string{"dominoes": "Domino's"},
+		Items:       map[string]string{"Chkn Biryani": "Chicken Biryani"},
+	}
+	got := NewNormalizer(aliases).Apply(orders)
+
+	wantRestaurants := []string{"Domino's Pizza", "Domino's Pizza", "Domino's Pizza", "Domino's", "Biryani Blues"}
+	for i, want := range wantRestaurants {
+		if got[i].Restaurant != want {
+			t.Errorf("order %d restaurant = %q, want %q", i, got[i].Restaurant, want)
+		}
+	}
+	// The alias target names the dish, however it is usually spelled.
+	for i, want := range []string{"Chicken Biryani", "Chicken Biryani", "Chicken Biryani"} {
+		if name := got[i+1].Items[0].Name; name != want {
+			t.Errorf("order %d item = %q, want %q", i+1, name, want)
+		}
+	}
+	if orders[2].Items[0].Name != "chicken biryani" {
+		t.Errorf("Apply modified its input: %q", orders[2].Items[0].Name)
+	}
+}
+
+func TestAddAndRemove(t *testing.T) {
+	m, err := Add(nil, "Dominoes", "Domino's Pizza")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Add(m, "DOMINOS PIZZA", "Domino's Pizza"); err == nil {
+		t.Error("Add of a name that already folds to its target succeeded, want error")
+	}
+	if _, err := Add(m, "Pizza Hut", "dominoes"); err == nil {
+		t.Error("Add targeting an alias succeeded, want error")
+	}
+
+	// Aliasing the target elsewhere re-points earlier aliases, so
+	// nothing chains.
+	m, err = Add(m, "Domino's Pizza", "Dominos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["Dominoes"] != "Dominos" || m["Domino's Pizza"] != "Dominos" {
+		t.Errorf("aliases = %v, want both pointing at Dominos", m)
+	}
+
+	m, ok := Remove(m, "dominoes")
+	if !ok || len(m) != 1 {
+		t.Errorf("Remove(dominoes) = %v, %v; want one alias left", m, ok)
+	}
+}
+
+func TestAliasesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	a, err := LoadAliases(path)
+	if err != nil || len(a.Restaurants) != 0 {
+		t.Fatalf("LoadAliases(missing) = %v, %v; want empty", a, err)
+	}
+	a.Items = map[string]string{"Chkn Biryani": "Chicken Biryani"}
+	if err := SaveAliases(path, a); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadAliases(path)
+	if err != nil || got.Items["Chkn Biryani"] != "Chicken Biryani" {
+		t.Errorf("LoadAliases = %v, %v", got, err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	counts := map[string]int{
+		"Domino's Pizza":     10,
+		"Dominoes Pizza":     2,
+		"Pizza Hut":          8,
+		"Pizza Hut Delivery": 1,
+		"Biryani Blues":      5,
+		"Dominos Pizza":      3, // folds with Domino's Pizza, never suggested
+	}
+	got := Suggest(counts, 0)
+	want := map[string]string{
+		"Dominoes Pizza":     "Domino's Pizza",
+		"Pizza Hut Delivery": "Pizza Hut",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Suggest = %+v, want %d suggestions", got, len(want))
+	}
+	for _, s := range got {
+		if want[s.From] != s.To {
+			t.Errorf("suggested %q → %q, want %q → %q", s.From, s.To, s.From, want[s.From])
+		}
+	}
+}
+
+func TestNormalizerKeepItemQualifiers(t *testing.T) {
+	orders := []zomato.Order{
+		{Restaurant: "Paradise - HSR", Items: []zomato.OrderItem{{Name: "Biryani (Half)", Quantity: 1}}},
+		{Restaurant: "Paradise", Items: []zomato.OrderItem{{Name: "Biryani (Full)", Quantity: 1}}},
+		{Restaurant: "Paradise", Items: []zomato.OrderItem{{Name: "biryani (full)", Quantity: 1}}},
+	}
+	n := NewNormalizer(Aliases{})
+	n.KeepItemQualifiers = true
+	got := n.Apply(orders)
+	for i, want := range []string{"Biryani (Half)", "Biryani (Full)", "Biryani (Full)"} {
+		if name := got[i].Items[0].Name; name != want {
+			t.Errorf("order %d item = %q, want %q", i, name, want)
+		}
+	}
+	if got[0].Restaurant != "Paradise" {
+		t.Errorf("restaurant = %q, want branches still folded", got[0].Restaurant)
+	}
+}
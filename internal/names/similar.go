@@ -0,0 +1,126 @@
+package names
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSimilarity is the similarity above which names are suggested as
+// aliases.
+const DefaultSimilarity = 0.8
+
+// Suggestion proposes counting From as To.
+type Suggestion struct {
+	From, To           string
+	FromCount, ToCount int
+	Similarity         float64
+}
+
+// Suggest finds pairs of names that are probably the same place or dish
+// but don't fold together, such as "Dominoes Pizza" and "Domino's Pizza".
+// counts maps each name to how often it occurs; the rarer name of a pair
+// is suggested as an alias for the more common one.
+func Suggest(counts map[string]int, minSimilarity float64) []Suggestion {
+	if minSimilarity <= 0 {
+		minSimilarity = DefaultSimilarity
+	}
+	type entry struct {
+		name, key string
+		count     int
+	}
+	// Names that already fold together are one entry, shown by their most
+	// common spelling.
+	byKey := map[string]*entry{}
+	best := map[string]int{}
+	for name, count := range counts {
+		key := Fold(name)
+		if key == "" {
+			continue
+		}
+		e, ok := byKey[key]
+		if !ok {
+			e = &entry{key: key}
+			byKey[key] = e
+		}
+		e.count += count
+		if count > best[key] || count == best[key] && name < e.name {
+			e.name, best[key] = name, count
+		}
+	}
+	entries := make([]entry, 0, len(byKey))
+	for _, e := range byKey {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	var out []Suggestion
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			a, b := entries[i], entries[j]
+			sim := similarity(a.key, b.key)
+			if sim < minSimilarity {
+				continue
+			}
+			out = append(out, Suggestion{
+				From: b.name, To: a.name,
+				FromCount: b.count, ToCount: a.count,
+				Similarity: sim,
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Similarity > out[j].Similarity })
+	return out
+}
+
+// similarity scores two folded names from 0 to 1 by edit distance, also
+// comparing them without spaces ("dominos" vs "domino s"). A name of two
+// or more words that starts the other scores 0.9 ("pizza hut" vs "pizza
+// hut express").
+func similarity(a, b string) float64 {
+	best := ratio(a, b)
+	if s := ratio(strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", "")); s > best {
+		best = s
+	}
+	short, long := a, b
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if strings.Contains(short, " ") && strings.HasPrefix(long, short+" ") && best < 0.9 {
+		best = 0.9
+	}
+	return best
+}
+
+func ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
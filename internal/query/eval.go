@@ -0,0 +1,626 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/filter"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// errNull marks comparisons with a missing value; they are false.
+var errNull = errors.New("null comparison")
+
+// row is one order, or one item of an order when the query uses item
+// fields.
+type row struct {
+	order *zomato.Order
+	item  *zomato.OrderItem
+}
+
+type fieldDef struct {
+	item bool // only defined per item
+	get  func(r row) Value
+}
+
+var fields = map[string]fieldDef{
+	"id":         {get: func(r row) Value { return text(r.order.ID) }},
+	"restaurant": {get: func(r row) Value { return text(r.order.Restaurant) }},
+	"status":     {get: func(r row) Value { return text(r.order.Status) }},
+	"placed_at":  {get: func(r row) Value { return timestamp(r.order.PlacedAt) }},
+	"total":      {get: func(r row) Value { return money(r.order.Total) }},
+	"currency": {get: func(r row) Value {
+		if r.order.Total.Currency == "" {
+			return text(zomato.DefaultCurrency)
+		}
+		return text(r.order.Total.Currency)
+	}},
+	"item_count": {get: func(r row) Value {
+		n := 0
+		for _, item := range r.order.Items {
+			n += max(item.Quantity, 1)
+		}
+		return number(float64(n))
+	}},
+	"item": {item: true, get: func(r row) Value { return text(r.item.Name) }},
+	"quantity": {item: true, get: func(r row) Value {
+		return number(float64(max(r.item.Quantity, 1)))
+	}},
+	"unit_price": {item: true, get: func(r row) Value {
+		if r.item.UnitPrice.IsZero() {
+			return null()
+		}
+		return money(r.item.UnitPrice)
+	}},
+	"item_total": {item: true, get: func(r row) Value {
+		if r.item.TotalPrice.IsZero() {
+			return null()
+		}
+		return money(r.item.TotalPrice)
+	}},
+}
+
+func fieldNames() string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type function struct {
+	arity int
+	apply func(args []Value) (Value, error)
+}
+
+func timeFunc(layout func(t time.Time) Value) function {
+	return function{arity: 1, apply: func(args []Value) (Value, error) {
+		switch args[0].Kind {
+		case KindNull:
+			return null(), nil
+		case KindTime:
+			return layout(args[0].Time), nil
+		}
+		return null(), fmt.Errorf("expected a time, got %s", args[0].Kind)
+	}}
+}
+
+var functions = map[string]function{
+	"year":    timeFunc(func(t time.Time) Value { return text(t.Format("2006")) }),
+	"month":   timeFunc(func(t time.Time) Value { return text(t.Format("2006-01")) }),
+	"day":     timeFunc(func(t time.Time) Value { return text(t.Format("2006-01-02")) }),
+	"weekday": timeFunc(func(t time.Time) Value { return text(t.Weekday().String()) }),
+	"hour":    timeFunc(func(t time.Time) Value { return number(float64(t.Hour())) }),
+	"lower": {arity: 1, apply: func(args []Value) (Value, error) {
+		if args[0].Kind == KindNull {
+			return null(), nil
+		}
+		return text(strings.ToLower(args[0].String())), nil
+	}},
+}
+
+// aggregates fold the values of a group; count() with no argument counts
+// rows.
+var aggregates = map[string]func(values []Value) (Value, error){
+	"count": func(values []Value) (Value, error) {
+		n := 0
+		for _, v := range values {
+			if v.Kind != KindNull {
+				n++
+			}
+		}
+		return number(float64(n)), nil
+	},
+	"sum": func(values []Value) (Value, error) { return fold(values, "sum") },
+	"avg": func(values []Value) (Value, error) { return fold(values, "avg") },
+	"min": func(values []Value) (Value, error) { return extreme(values, -1) },
+	"max": func(values []Value) (Value, error) { return extreme(values, 1) },
+}
+
+func fold(values []Value, name string) (Value, error) {
+	var total zomato.Money
+	var num float64
+	n, moneyCount := 0, 0
+	for _, v := range values {
+		switch v.Kind {
+		case KindNull:
+			continue
+		case KindMoney:
+			sum, err := total.CheckedAdd(v.Money)
+			if err != nil {
+				return null(), fmt.Errorf("%s() mixes %s and %s amounts; add currency to group by", name, total.Currency, v.Money.Currency)
+			}
+			total = sum
+			moneyCount++
+		case KindNumber:
+			num += v.Num
+		default:
+			return null(), fmt.Errorf("%s() needs numbers or amounts, got %s", name, v.Kind)
+		}
+		n++
+	}
+	if moneyCount > 0 && moneyCount != n {
+		return null(), fmt.Errorf("%s() mixes amounts and plain numbers", name)
+	}
+	if n == 0 {
+		return null(), nil
+	}
+	if moneyCount > 0 {
+		if name == "avg" {
+			return money(total.Div(n)), nil
+		}
+		return money(total), nil
+	}
+	if name == "avg" {
+		return number(num / float64(n)), nil
+	}
+	return number(num), nil
+}
+
+func extreme(values []Value, sign int) (Value, error) {
+	best := null()
+	for _, v := range values {
+		if v.Kind == KindNull {
+			continue
+		}
+		if best.Kind == KindNull {
+			best = v
+			continue
+		}
+		if v.Kind == KindMoney && best.Kind == KindMoney && v.Money.Currency != best.Money.Currency {
+			return null(), fmt.Errorf("cannot compare %s and %s amounts; add currency to group by", best.Money.Currency, v.Money.Currency)
+		}
+		c, err := compare(v, best)
+		if err != nil {
+			return null(), err
+		}
+		if c*sign > 0 {
+			best = v
+		}
+	}
+	return best, nil
+}
+
+// evalRow evaluates a non-aggregate expression for one row.
+func evalRow(e expr, r row) (Value, error) {
+	switch e := e.(type) {
+	case literal:
+		return e.value, nil
+	case fieldRef:
+		return fields[e.name].get(r), nil
+	case call:
+		if _, ok := aggregates[e.name]; ok {
+			return null(), fmt.Errorf("%s() is an aggregate and only works in select", e.name)
+		}
+		args := make([]Value, len(e.args))
+		for i, a := range e.args {
+			v, err := evalRow(a, r)
+			if err != nil {
+				return null(), err
+			}
+			args[i] = v
+		}
+		return applyFunc(e, args)
+	case not:
+		v, err := evalRow(e.operand, r)
+		if err != nil {
+			return null(), err
+		}
+		return boolean(!v.truthy()), nil
+	case binary:
+		left, err := evalRow(e.left, r)
+		if err != nil {
+			return null(), err
+		}
+		// Short-circuit, so "a and b" never evaluates b for rows a drops.
+		switch e.op {
+		case "and":
+			if !left.truthy() {
+				return boolean(false), nil
+			}
+		case "or":
+			if left.truthy() {
+				return boolean(true), nil
+			}
+		}
+		right, err := evalRow(e.right, r)
+		if err != nil {
+			return null(), err
+		}
+		return applyOp(e.op, left, right)
+	}
+	return null(), fmt.Errorf("cannot evaluate %s", e)
+}
+
+// evalGroup evaluates an expression over the rows of a group: aggregates
+// fold every row, anything else is read from the first row. Aggregates of
+// order fields, and count(), see each order once even when the rows are
+// per item.
+func evalGroup(e expr, rows []row) (Value, error) {
+	switch e := e.(type) {
+	case call:
+		if agg, ok := aggregates[e.name]; ok {
+			if !usesItem(e) {
+				rows = distinctOrders(rows)
+			}
+			values := make([]Value, len(rows))
+			for i, r := range rows {
+				if e.star || len(e.args) == 0 {
+					values[i] = boolean(true)
+					continue
+				}
+				v, err := evalRow(e.args[0], r)
+				if err != nil {
+					return null(), err
+				}
+				values[i] = v
+			}
+			return agg(values)
+		}
+		args := make([]Value, len(e.args))
+		for i, a := range e.args {
+			v, err := evalGroup(a, rows)
+			if err != nil {
+				return null(), err
+			}
+			args[i] = v
+		}
+		return applyFunc(e, args)
+	case binary:
+		left, err := evalGroup(e.left, rows)
+		if err != nil {
+			return null(), err
+		}
+		right, err := evalGroup(e.right, rows)
+		if err != nil {
+			return null(), err
+		}
+		return applyOp(e.op, left, right)
+	case not:
+		v, err := evalGroup(e.operand, rows)
+		if err != nil {
+			return null(), err
+		}
+		return boolean(!v.truthy()), nil
+	}
+	if len(rows) == 0 {
+		return null(), nil
+	}
+	return evalRow(e, rows[0])
+}
+
+// distinctOrders keeps the first row of each order.
+func distinctOrders(rows []row) []row {
+	seen := map[*zomato.Order]bool{}
+	out := make([]row, 0, len(rows))
+	for _, r := range rows {
+		if !seen[r.order] {
+			seen[r.order] = true
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func applyFunc(c call, args []Value) (Value, error) {
+	v, err := functions[c.name].apply(args)
+	if err != nil {
+		return null(), fmt.Errorf("%s: %w", c, err)
+	}
+	return v, nil
+}
+
+func applyOp(op string, left, right Value) (Value, error) {
+	switch op {
+	case "and":
+		return boolean(left.truthy() && right.truthy()), nil
+	case "or":
+		return boolean(left.truthy() || right.truthy()), nil
+	case "~", "!~":
+		if left.Kind == KindNull {
+			return boolean(false), nil
+		}
+		if right.Kind != KindString {
+			return null(), fmt.Errorf("%s needs a text pattern on the right", op)
+		}
+		m, err := filter.NewMatcher(right.Str)
+		if err != nil {
+			return null(), err
+		}
+		return boolean(m.Match(left.String()) == (op == "~")), nil
+	}
+	if left.Kind == KindNull || right.Kind == KindNull {
+		// Only "x = null" and "x != null" are true for missing values.
+		both := left.Kind == right.Kind
+		switch op {
+		case "=":
+			return boolean(both), nil
+		case "!=":
+			return boolean(!both), nil
+		}
+		return boolean(false), nil
+	}
+	c, err := compare(left, right)
+	if err != nil {
+		return null(), err
+	}
+	switch op {
+	case "=":
+		return boolean(c == 0), nil
+	case "!=":
+		return boolean(c != 0), nil
+	case "<":
+		return boolean(c < 0), nil
+	case "<=":
+		return boolean(c <= 0), nil
+	case ">":
+		return boolean(c > 0), nil
+	case ">=":
+		return boolean(c >= 0), nil
+	}
+	return null(), fmt.Errorf("unknown operator %s", op)
+}
+
+// Result is a query's output table.
+type Result struct {
+	Columns []string
+	Rows    [][]Value
+}
+
+// Default columns when a query has no select clause.
+var (
+	defaultOrderColumns = []string{"id", "placed_at", "restaurant", "status", "total"}
+	defaultItemColumns  = []string{"id", "placed_at", "restaurant", "item", "quantity", "unit_price"}
+)
+
+// Run evaluates q over orders.
+func (q *Query) Run(orders []zomato.Order) (*Result, error) {
+	perItem := q.usesItemFields()
+
+	var rows []row
+	for i := range orders {
+		order := &orders[i]
+		if !perItem {
+			rows = append(rows, row{order: order})
+			continue
+		}
+		for j := range order.Items {
+			rows = append(rows, row{order: order, item: &order.Items[j]})
+		}
+	}
+
+	if q.Where != nil {
+		kept := rows[:0:0]
+		for _, r := range rows {
+			v, err := evalRow(q.Where, r)
+			if err != nil {
+				return nil, err
+			}
+			if v.truthy() {
+				kept = append(kept, r)
+			}
+		}
+		rows = kept
+	}
+
+	selects := q.Select
+	grouped := len(q.GroupBy) > 0
+	aggregated := grouped
+	for _, c := range selects {
+		if hasAggregate(c.expr) {
+			aggregated = true
+		}
+	}
+	if grouped {
+		// Group keys lead, so each row says which group it summarizes.
+		var keys []column
+		for _, g := range q.GroupBy {
+			if !selected(selects, g) {
+				keys = append(keys, column{expr: g, name: g.String()})
+			}
+		}
+		selects = append(keys, selects...)
+		if len(q.Select) == 0 {
+			count := call{name: "count", star: true}
+			selects = append(selects, column{expr: count, name: count.String()})
+		}
+	} else if len(selects) == 0 {
+		names := defaultOrderColumns
+		if perItem {
+			names = defaultItemColumns
+		}
+		for _, name := range names {
+			selects = append(selects, column{expr: fieldRef{name: name}, name: name})
+		}
+	}
+
+	res := &Result{}
+	for _, c := range selects {
+		res.Columns = append(res.Columns, c.name)
+	}
+
+	if aggregated {
+		groups, err := q.group(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			out := make([]Value, len(selects))
+			for i, c := range selects {
+				v, err := evalGroup(c.expr, g)
+				if err != nil {
+					return nil, err
+				}
+				out[i] = v
+			}
+			res.Rows = append(res.Rows, out)
+		}
+	} else {
+		for _, r := range rows {
+			out := make([]Value, len(selects))
+			for i, c := range selects {
+				v, err := evalRow(c.expr, r)
+				if err != nil {
+					return nil, err
+				}
+				out[i] = v
+			}
+			res.Rows = append(res.Rows, out)
+		}
+	}
+
+	if err := q.sort(res); err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(res.Rows) > q.Limit {
+		res.Rows = res.Rows[:q.Limit]
+	}
+	return res, nil
+}
+
+func selected(columns []column, e expr) bool {
+	for _, c := range columns {
+		if c.expr.String() == e.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// group splits rows by the group-by keys, ordered by key. Without group
+// by, all rows form one group.
+func (q *Query) group(rows []row) ([][]row, error) {
+	if len(q.GroupBy) == 0 {
+		return [][]row{rows}, nil
+	}
+	type bucket struct {
+		keys []Value
+		rows []row
+	}
+	index := map[string]*bucket{}
+	var buckets []*bucket
+	for _, r := range rows {
+		keys := make([]Value, len(q.GroupBy))
+		parts := make([]string, len(q.GroupBy))
+		for i, g := range q.GroupBy {
+			v, err := evalRow(g, r)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = v
+			parts[i] = fmt.Sprintf("%d:%s", v.Kind, v.String())
+		}
+		id := strings.Join(parts, "\x00")
+		b, ok := index[id]
+		if !ok {
+			b = &bucket{keys: keys}
+			index[id] = b
+			buckets = append(buckets, b)
+		}
+		b.rows = append(b.rows, r)
+	}
+	sort.SliceStable(buckets, func(i, j int) bool {
+		for k := range buckets[i].keys {
+			c := compareForSort(buckets[i].keys[k], buckets[j].keys[k])
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	out := make([][]row, len(buckets))
+	for i, b := range buckets {
+		out[i] = b.rows
+	}
+	return out, nil
+}
+
+func (q *Query) sort(res *Result) error {
+	if len(q.OrderBy) == 0 {
+		return nil
+	}
+	cols := make([]int, len(q.OrderBy))
+	for i, o := range q.OrderBy {
+		cols[i] = -1
+		for j, name := range res.Columns {
+			if strings.EqualFold(name, o.name) {
+				cols[i] = j
+				break
+			}
+		}
+		if cols[i] < 0 {
+			return fmt.Errorf("order by %s: not a selected column (columns: %s)", o.name, strings.Join(res.Columns, ", "))
+		}
+	}
+	sort.SliceStable(res.Rows, func(a, b int) bool {
+		for i, o := range q.OrderBy {
+			c := compareForSort(res.Rows[a][cols[i]], res.Rows[b][cols[i]])
+			if c == 0 {
+				continue
+			}
+			if o.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// compareForSort is compare with nulls last and incomparable values
+// ordered by their text.
+func compareForSort(a, b Value) int {
+	switch {
+	case a.Kind == KindNull && b.Kind == KindNull:
+		return 0
+	case a.Kind == KindNull:
+		return 1
+	case b.Kind == KindNull:
+		return -1
+	}
+	if c, err := compare(a, b); err == nil {
+		return c
+	}
+	return strings.Compare(a.String(), b.String())
+}
+
+func (q *Query) usesItemFields() bool {
+	if q.Where != nil && usesItem(q.Where) {
+		return true
+	}
+	for _, g := range q.GroupBy {
+		if usesItem(g) {
+			return true
+		}
+	}
+	for _, c := range q.Select {
+		if usesItem(c.expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesItem reports whether e reads an item field.
+func usesItem(e expr) bool {
+	switch e := e.(type) {
+	case fieldRef:
+		return fields[e.name].item
+	case call:
+		for _, a := range e.args {
+			if usesItem(a) {
+				return true
+			}
+		}
+	case binary:
+		return usesItem(e.left) || usesItem(e.right)
+	case not:
+		return usesItem(e.operand)
+	}
+	return false
+}
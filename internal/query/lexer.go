@@ -0,0 +1,109 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokStar
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether t is the keyword or operator s (case-insensitive).
+func (t token) is(s string) bool {
+	return (t.kind == tokIdent || t.kind == tokOp) && strings.EqualFold(t.text, s)
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+		case r == '*':
+			tokens = append(tokens, token{tokStar, "*", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, token{tokString, b.String(), start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i]), start})
+		default:
+			start := i
+			op := string(r)
+			if i+1 < len(runes) {
+				two := string(runes[i : i+2])
+				switch two {
+				case "<=", ">=", "!=", "==", "<>", "!~":
+					op = two
+				}
+			}
+			switch op {
+			case "=", "==", "!=", "<>", "<", "<=", ">", ">=", "~", "!~":
+			default:
+				return nil, fmt.Errorf("unexpected %q at position %d", op, start+1)
+			}
+			i += len([]rune(op))
+			tokens = append(tokens, token{tokOp, op, start})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes)}), nil
+}
@@ -0,0 +1,444 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query is a parsed query:
+//
+//	[FILTER] [group by EXPR, ...] [select EXPR [as NAME], ...]
+//	[order by NAME [asc|desc], ...] [limit N]
+type Query struct {
+	Where   expr
+	GroupBy []expr
+	Select  []column
+	OrderBy []ordering
+	Limit   int
+}
+
+type column struct {
+	expr expr
+	name string
+}
+
+type ordering struct {
+	name string
+	desc bool
+}
+
+// expr is a node of the expression tree. String returns its canonical
+// text, used as the default column name.
+type expr interface {
+	String() string
+}
+
+type literal struct{ value Value }
+
+type fieldRef struct{ name string }
+
+type call struct {
+	name string
+	args []expr
+	star bool // count(*)
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+type not struct{ operand expr }
+
+func (l literal) String() string {
+	if l.value.Kind == KindString {
+		return strconv.Quote(l.value.Str)
+	}
+	return l.value.String()
+}
+
+func (f fieldRef) String() string { return f.name }
+
+func (c call) String() string {
+	if c.star {
+		return c.name + "()"
+	}
+	args := make([]string, len(c.args))
+	for i, a := range c.args {
+		args[i] = a.String()
+	}
+	return c.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (b binary) String() string {
+	return b.left.String() + " " + b.op + " " + b.right.String()
+}
+
+func (n not) String() string { return "not " + n.operand.String() }
+
+// Parse parses a query string.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(keyword string) error {
+	if !p.accept(keyword) {
+		return p.errorf("expected %q", keyword)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	return fmt.Errorf("%s at position %d near %s", fmt.Sprintf(format, args...), t.pos+1, t)
+}
+
+// atClause reports whether the next token starts a clause.
+func (p *parser) atClause() bool {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return true
+	}
+	for _, kw := range []string{"group", "select", "order", "limit"} {
+		if t.is(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+	p.accept("where")
+	if !p.atClause() {
+		where, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		q.Where = where
+	}
+	for p.peek().kind != tokEOF {
+		switch {
+		case p.accept("group"):
+			if err := p.expect("by"); err != nil {
+				return nil, err
+			}
+			list, err := p.exprList()
+			if err != nil {
+				return nil, err
+			}
+			q.GroupBy = append(q.GroupBy, list...)
+		case p.accept("select"):
+			for {
+				e, err := p.or()
+				if err != nil {
+					return nil, err
+				}
+				col := column{expr: e, name: e.String()}
+				if p.accept("as") {
+					t := p.next()
+					if t.kind != tokIdent && t.kind != tokString {
+						return nil, p.errorf("expected a column name after as")
+					}
+					col.name = t.text
+				}
+				q.Select = append(q.Select, col)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.next()
+			}
+		case p.accept("order"):
+			if err := p.expect("by"); err != nil {
+				return nil, err
+			}
+			for {
+				var o ordering
+				if t := p.peek(); (t.kind == tokIdent && p.tokens[p.pos+1].kind != tokLParen) || t.kind == tokString {
+					// A column name or alias rather than an expression.
+					p.next()
+					o.name = t.text
+				} else {
+					e, err := p.or()
+					if err != nil {
+						return nil, err
+					}
+					o.name = e.String()
+				}
+				if p.accept("desc") {
+					o.desc = true
+				} else {
+					p.accept("asc")
+				}
+				q.OrderBy = append(q.OrderBy, o)
+				if p.peek().kind != tokComma {
+					break
+				}
+				p.next()
+			}
+		case p.accept("limit"):
+			t := p.next()
+			n, err := strconv.Atoi(t.text)
+			if t.kind != tokNumber || err != nil || n < 0 {
+				return nil, fmt.Errorf("limit needs a whole number, got %s", t)
+			}
+			q.Limit = n
+		default:
+			return nil, p.errorf("unexpected token")
+		}
+	}
+	return q, nil
+}
+
+func (p *parser) exprList() ([]expr, error) {
+	var list []expr
+	for {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if p.peek().kind != tokComma {
+			return list, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) or() (expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (expr, error) {
+	if p.accept("not") {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokOp {
+		p.next()
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		switch op {
+		case "==":
+			op = "="
+		case "<>":
+			op = "!="
+		}
+		return binary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) operand() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t)
+		}
+		return literal{number(n)}, nil
+	case tokString:
+		return literal{text(t.text)}, nil
+	case tokLParen:
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, p.errorf("expected )")
+		}
+		return e, nil
+	case tokIdent:
+		name := strings.ToLower(t.text)
+		switch name {
+		case "true":
+			return literal{boolean(true)}, nil
+		case "false":
+			return literal{boolean(false)}, nil
+		case "null":
+			return literal{null()}, nil
+		}
+		if p.peek().kind != tokLParen {
+			if _, ok := fields[name]; !ok {
+				return nil, fmt.Errorf("unknown field %q (fields: %s)", t.text, fieldNames())
+			}
+			return fieldRef{name: name}, nil
+		}
+		p.next()
+		c := call{name: name}
+		if _, ok := functions[name]; !ok {
+			if _, ok := aggregates[name]; !ok {
+				return nil, fmt.Errorf("unknown function %q", t.text)
+			}
+		}
+		switch p.peek().kind {
+		case tokRParen:
+			p.next()
+			c.star = true
+		case tokStar:
+			p.next()
+			if p.next().kind != tokRParen {
+				return nil, p.errorf("expected )")
+			}
+			c.star = true
+		default:
+			args, err := p.exprList()
+			if err != nil {
+				return nil, err
+			}
+			if p.next().kind != tokRParen {
+				return nil, p.errorf("expected )")
+			}
+			c.args = args
+		}
+		return c, nil
+	}
+	p.pos--
+	return nil, p.errorf("expected a field, value or function")
+}
+
+// validate checks function arity and where aggregates may appear.
+func (q *Query) validate() error {
+	if q.Where != nil && hasAggregate(q.Where) {
+		return fmt.Errorf("aggregates such as count() belong in select, not the filter")
+	}
+	for _, g := range q.GroupBy {
+		if hasAggregate(g) {
+			return fmt.Errorf("cannot group by an aggregate: %s", g)
+		}
+	}
+	var err error
+	var check func(e expr)
+	check = func(e expr) {
+		switch e := e.(type) {
+		case call:
+			if _, ok := aggregates[e.name]; ok {
+				if e.name != "count" && len(e.args) != 1 {
+					err = fmt.Errorf("%s() takes one argument", e.name)
+				}
+				if e.name == "count" && len(e.args) > 1 {
+					err = fmt.Errorf("count() takes at most one argument")
+				}
+			} else if f := functions[e.name]; len(e.args) != f.arity {
+				err = fmt.Errorf("%s() takes %d argument(s)", e.name, f.arity)
+			}
+			for _, a := range e.args {
+				check(a)
+			}
+		case binary:
+			check(e.left)
+			check(e.right)
+		case not:
+			check(e.operand)
+		}
+	}
+	all := append([]expr{}, q.GroupBy...)
+	if q.Where != nil {
+		all = append(all, q.Where)
+	}
+	for _, c := range q.Select {
+		all = append(all, c.expr)
+	}
+	for _, e := range all {
+		if check(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasAggregate(e expr) bool {
+	switch e := e.(type) {
+	case call:
+		if _, ok := aggregates[e.name]; ok {
+			return true
+		}
+		for _, a := range e.args {
+			if hasAggregate(a) {
+				return true
+			}
+		}
+	case binary:
+		return hasAggregate(e.left) || hasAggregate(e.right)
+	case not:
+		return hasAggregate(e.operand)
+	}
+	return false
+}
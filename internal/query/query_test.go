@@ -0,0 +1,156 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func testOrders() []zomato.Order {
+	at := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 20, 0, 0, 0, time.UTC) }
+	inr := func(major int64) zomato.Money { return zomato.Money{Amount: major * 100, Currency: "INR"} }
+	return []zomato.Order{
+		{ID: "1", Restaurant: "Pizza Hut", Status: "Delivered", PlacedAt: at(1, 5), Total: inr(600),
+			Items: []zomato.OrderItem{{Name: "Margherita", Quantity: 2, UnitPrice: inr(250)}}},
+		{ID: "2", Restaurant: "La Pino'z Pizza", Status: "Delivered", PlacedAt: at(1, 20), Total: inr(400),
+			Items: []zomato.OrderItem{{Name: "Farmhouse", Quantity: 1}}},
+		{ID: "3", Restaurant: "Pizza Hut", Status: "Delivered", PlacedAt: at(2, 3), Total: inr(900),
+			Items: []zomato.OrderItem{{Name: "Margherita", Quantity: 1}, {Name: "Garlic Bread", Quantity: 1}}},
+		{ID: "4", Restaurant: "Biryani Blues", Status: "Cancelled", PlacedAt: at(2, 10), Total: inr(350),
+			Items: []zomato.OrderItem{{Name: "Chicken Biryani", Quantity: 1}}},
+	}
+}
+
+func run(t *testing.T, input string) *Result {
+	t.Helper()
+	q, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", input, err)
+	}
+	res, err := q.Run(testOrders())
+	if err != nil {
+		t.Fatalf("Run(%q) failed: %v", input, err)
+	}
+	return res
+}
+
+// table renders a result as "col|col" lines for comparison.
+func table(res *Result) string {
+	lines := []string{strings.Join(res.Columns, "|")}
+	for _, row := range res.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = v.String()
+		}
+		lines = append(lines, strings.Join(cells, "|"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			`restaurant ~ "pizza" and total > 500 group by month(placed_at) select count(), sum(total)`,
+			"month(placed_at)|count()|sum(total)\n2024-01|1|₹600.00\n2024-02|1|₹900.00",
+		},
+		{
+			`status != "cancelled" group by restaurant select avg(total) as avg order by avg desc`,
+			"restaurant|avg\nPizza Hut|₹750.00\nLa Pino'z Pizza|₹400.00",
+		},
+		{
+			`group by item select sum(quantity) as qty order by qty desc, item limit 2`,
+			"item|qty\nMargherita|3\nChicken Biryani|1",
+		},
+		{
+			`placed_at >= "2024-02-01" select id, restaurant`,
+			"id|restaurant\n3|Pizza Hut\n4|Biryani Blues",
+		},
+		{
+			`restaurant ~ "/^pizza/" and not item_count > 2 and placed_at < "2024-02"`,
+			"id|placed_at|restaurant|status|total\n1|2024-01-05 20:00|Pizza Hut|Delivered|₹600.00",
+		},
+		{
+			`select count(), min(total), max(placed_at)`,
+			"count()|min(total)|max(placed_at)\n4|₹350.00|2024-02-10 20:00",
+		},
+		{
+			`unit_price = null select id, item`,
+			"id|item\n2|Farmhouse\n3|Margherita\n3|Garlic Bread\n4|Chicken Biryani",
+		},
+		{
+			`group by weekday(placed_at)`,
+			"weekday(placed_at)|count()\nFriday|1\nSaturday|3",
+		},
+		// Item fields give a row per item; order fields and count() still
+		// see each order once.
+		{
+			`group by restaurant select sum(total), sum(quantity)`,
+			"restaurant|sum(total)|sum(quantity)\nBiryani Blues|₹350.00|1\nLa Pino'z Pizza|₹400.00|1\nPizza Hut|₹1500.00|4",
+		},
+		{
+			`restaurant ~ "pizza hut" and item != "x" select count(), sum(total), count(item)`,
+			"count()|sum(total)|count(item)\n2|₹1500.00|3",
+		},
+	}
+	for _, tt := range tests {
+		if got := table(run(t, tt.query)); got != tt.want {
+			t.Errorf("query %q:\n%s\nwant:\n%s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`price > 5`, "unknown field"},
+		{`median(total) > 5`, "unknown function"},
+		{`count() > 1`, "belong in select"},
+		{`group by sum(total)`, "cannot group by an aggregate"},
+		{`select month()`, "takes 1 argument"},
+		{`select sum()`, "takes one argument"},
+		{`restaurant = "x`, "unterminated string"},
+		{`total > 5 limit ten`, "limit needs a whole number"},
+		{`total >`, "expected a field"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want containing %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestRunMixedCurrencies(t *testing.T) {
+	orders := append(testOrders(), zomato.Order{
+		ID: "5", Restaurant: "Dubai Grill", PlacedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Total: zomato.Money{Amount: 4000, Currency: "AED"},
+	})
+
+	q, err := Parse(`select sum(total)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Run(orders); err == nil || !strings.Contains(err.Error(), "group by") {
+		t.Errorf("sum over mixed currencies error = %v, want a group by hint", err)
+	}
+
+	q, err = Parse(`group by currency select sum(total)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := q.Run(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "currency|sum(total)\nAED|AED 40.00\nINR|₹2250.00"
+	if got := table(res); got != want {
+		t.Errorf("per-currency sum:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Kind is the type of a Value.
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindNumber
+	KindString
+	KindTime
+	KindMoney
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindBool:
+		return "boolean"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindTime:
+		return "time"
+	case KindMoney:
+		return "amount"
+	default:
+		return "null"
+	}
+}
+
+// Value is a dynamically typed query value.
+type Value struct {
+	Kind  Kind
+	Bool  bool
+	Num   float64
+	Str   string
+	Time  time.Time
+	Money zomato.Money
+}
+
+func null() Value                { return Value{} }
+func boolean(b bool) Value       { return Value{Kind: KindBool, Bool: b} }
+func number(n float64) Value     { return Value{Kind: KindNumber, Num: n} }
+func text(s string) Value        { return Value{Kind: KindString, Str: s} }
+func money(m zomato.Money) Value { return Value{Kind: KindMoney, Money: m} }
+func timestamp(t time.Time) Value {
+	if t.IsZero() {
+		return null()
+	}
+	return Value{Kind: KindTime, Time: t}
+}
+
+// String formats the value for tables.
+func (v Value) String() string {
+	switch v.Kind {
+	case KindBool:
+		return strconv.FormatBool(v.Bool)
+	case KindNumber:
+		return formatNumber(v.Num)
+	case KindString:
+		return v.Str
+	case KindTime:
+		return v.Time.Format("2006-01-02 15:04")
+	case KindMoney:
+		return v.Money.String()
+	default:
+		return "-"
+	}
+}
+
+// Raw returns the value for machine-readable output: amounts become exact
+// decimal numbers in major units.
+func (v Value) Raw() any {
+	switch v.Kind {
+	case KindBool:
+		return v.Bool
+	case KindNumber:
+		return json.Number(formatNumber(v.Num))
+	case KindString:
+		return v.Str
+	case KindTime:
+		return v.Time
+	case KindMoney:
+		return json.Number(v.Money.Number())
+	default:
+		return nil
+	}
+}
+
+func formatNumber(n float64) string {
+	if n == float64(int64(n)) {
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return strconv.FormatFloat(n, 'f', 2, 64)
+}
+
+// truthy reports whether a filter result keeps the row.
+func (v Value) truthy() bool {
+	switch v.Kind {
+	case KindBool:
+		return v.Bool
+	case KindNull:
+		return false
+	default:
+		return true
+	}
+}
+
+// numeric returns the value as a float for comparisons and sums.
+func (v Value) numeric() (float64, bool) {
+	switch v.Kind {
+	case KindNumber:
+		return v.Num, true
+	case KindMoney:
+		return v.Money.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// compare orders a and b, returning -1, 0 or 1. Strings compare
+// case-insensitively; a string compared with a time is read as a date.
+func compare(a, b Value) (int, error) {
+	if a.Kind == KindNull || b.Kind == KindNull {
+		return 0, errNull
+	}
+	if a.Kind == KindTime && b.Kind == KindString {
+		t, err := parseTimeLiteral(b.Str, a.Time.Location())
+		if err != nil {
+			return 0, err
+		}
+		b = timestamp(t)
+	}
+	if a.Kind == KindString && b.Kind == KindTime {
+		c, err := compare(b, a)
+		return -c, err
+	}
+	if an, ok := a.numeric(); ok {
+		if bn, ok := b.numeric(); ok {
+			switch {
+			case an < bn:
+				return -1, nil
+			case an > bn:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	switch {
+	case a.Kind == KindString && b.Kind == KindString:
+		return strings.Compare(strings.ToLower(a.Str), strings.ToLower(b.Str)), nil
+	case a.Kind == KindTime && b.Kind == KindTime:
+		return a.Time.Compare(b.Time), nil
+	case a.Kind == KindBool && b.Kind == KindBool:
+		switch {
+		case a.Bool == b.Bool:
+			return 0, nil
+		case !a.Bool:
+			return -1, nil
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", a.Kind, b.Kind)
+}
+
+// parseTimeLiteral reads "2024-01-15", "2024-01-15 20:30" or RFC3339.
+func parseTimeLiteral(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02", "2006-01", "2006"} {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use 2024-01-15)", s)
+}
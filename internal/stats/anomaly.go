@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// DefaultAnomalyThreshold is the robust z-score beyond which a value is
+// flagged, the usual cut-off for the modified z-score.
+const DefaultAnomalyThreshold = 3.5
+
+// minAnomalySamples is how many months, weeks or orders a series needs
+// before its outliers mean anything.
+const minAnomalySamples = 6
+
+// Anomaly kinds.
+const (
+	AnomalyMonth = "month"
+	AnomalyWeek  = "week"
+	AnomalyOrder = "order"
+)
+
+// Anomaly is a month, week or order whose spend deviates strongly from the
+// rest.
+type Anomaly struct {
+	Kind       string
+	Key        string // "Mar 2024", "2024-W10" or the order ID
+	Start      time.Time
+	Restaurant string // orders only
+	Orders     int
+	Total      zomato.Money
+	Typical    zomato.Money // median of the series
+	// Score is the modified z-score: positive for unusually high spend,
+	// negative for unusually low.
+	Score float64
+}
+
+// DetectAnomalies flags months and weeks with unusually high or low spend
+// and orders with unusually high totals, using the modified z-score
+// (distance from the median in units of median absolute deviation), so a
+// few outliers can't hide each other by inflating the spread. Periods are
+// computed in now's location; orders should share a currency.
+//
+// The month and week containing now are still in progress: they are left
+// out of the baseline and only flagged when already unusually high.
+func DetectAnomalies(orders []zomato.Order, now time.Time, threshold float64) []Anomaly {
+	if threshold <= 0 {
+		threshold = DefaultAnomalyThreshold
+	}
+	loc := now.Location()
+	var out []Anomaly
+
+	var last time.Time
+	for _, order := range orders {
+		if order.PlacedAt.After(last) {
+			last = order.PlacedAt
+		}
+	}
+	if !last.IsZero() {
+		months := MonthlyHistory(orders, monthStart(last.In(loc)).AddDate(0, 1, 0), loc)
+		out = append(out, periodAnomalies(AnomalyMonth, months, monthStart(now), threshold, func(t time.Time) string {
+			return t.Format("Jan 2006")
+		})...)
+		weeks := WeeklyHistory(orders, loc)
+		out = append(out, periodAnomalies(AnomalyWeek, weeks, weekStart(now), threshold, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		})...)
+	}
+
+	values := make([]float64, 0, len(orders))
+	for _, order := range orders {
+		values = append(values, float64(order.Total.Amount))
+	}
+	if median, scale, ok := robustScale(values); ok {
+		var flagged []Anomaly
+		for _, order := range orders {
+			score := (float64(order.Total.Amount) - median) / scale
+			if score < threshold {
+				continue
+			}
+			flagged = append(flagged, Anomaly{
+				Kind:       AnomalyOrder,
+				Key:        order.ID,
+				Start:      order.PlacedAt,
+				Restaurant: order.Restaurant,
+				Orders:     1,
+				Total:      order.Total,
+				Typical:    zomato.Money{Amount: int64(math.Round(median)), Currency: order.Total.Currency},
+				Score:      score,
+			})
+		}
+		sort.SliceStable(flagged, func(i, j int) bool { return flagged[i].Start.Before(flagged[j].Start) })
+		out = append(out, flagged...)
+	}
+	return out
+}
+
+// periodAnomalies scores periods against the complete ones, those that
+// started before current. Periods from current on are still in progress,
+// so only high spend is flagged for them.
+func periodAnomalies(kind string, periods []Period, current time.Time, threshold float64, key func(time.Time) string) []Anomaly {
+	values := make([]float64, 0, len(periods))
+	for _, p := range periods {
+		if p.Start.Before(current) {
+			values = append(values, float64(p.Total.Amount))
+		}
+	}
+	median, scale, ok := robustScale(values)
+	if !ok {
+		return nil
+	}
+	var out []Anomaly
+	for _, p := range periods {
+		score := (float64(p.Total.Amount) - median) / scale
+		if math.Abs(score) < threshold || (score < 0 && !p.Start.Before(current)) {
+			continue
+		}
+		out = append(out, Anomaly{
+			Kind:    kind,
+			Key:     key(p.Start),
+			Start:   p.Start,
+			Orders:  p.Count,
+			Total:   p.Total,
+			Typical: zomato.Money{Amount: int64(math.Round(median)), Currency: p.Total.Currency},
+			Score:   score,
+		})
+	}
+	return out
+}
+
+// robustScale returns the median of values and the scale of the modified
+// z-score: 1.4826 × the median absolute deviation, falling back to
+// 1.2533 × the mean absolute deviation when more than half the values are
+// equal. ok is false for short or constant series.
+func robustScale(values []float64) (median, scale float64, ok bool) {
+	if len(values) < minAnomalySamples {
+		return 0, 0, false
+	}
+	median = medianOf(values)
+	deviations := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+		sum += deviations[i]
+	}
+	if mad := medianOf(deviations); mad > 0 {
+		return median, 1.4826 * mad, true
+	}
+	if mean := sum / float64(len(values)); mean > 0 {
+		return median, 1.2533 * mean, true
+	}
+	return 0, 0, false
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	var orders []zomato.Order
+	prices := []int64{400, 450, 500, 550, 600}
+	for month := 1; month <= 12; month++ {
+		for i, price := range prices {
+			orders = append(orders, zomato.Order{
+				ID:         "regular",
+				Restaurant: "Dosa Corner",
+				PlacedAt:   time.Date(2024, time.Month(month), 3+i*5, 20, 0, 0, 0, time.UTC),
+				Total:      rupees(price),
+			})
+		}
+	}
+	orders = append(orders, zomato.Order{
+		ID:         "party",
+		Restaurant: "Biryani Blues",
+		PlacedAt:   time.Date(2024, 6, 14, 20, 0, 0, 0, time.UTC),
+		Total:      rupees(9000),
+	})
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	got := DetectAnomalies(orders, now, 0)
+	found := map[string]Anomaly{}
+	for _, a := range got {
+		found[a.Kind+" "+a.Key] = a
+	}
+	for _, key := range []string{"month Jun 2024", "week 2024-W24", "order party"} {
+		a, ok := found[key]
+		if !ok {
+			t.Errorf("DetectAnomalies missed %s; got %+v", key, got)
+			continue
+		}
+		if a.Score < DefaultAnomalyThreshold {
+			t.Errorf("%s score = %.1f, want at least %.1f", key, a.Score, DefaultAnomalyThreshold)
+		}
+	}
+	if a := found["order party"]; a.Restaurant != "Biryani Blues" || a.Typical != rupees(500) {
+		t.Errorf("party order = %+v, want Biryani Blues with a typical ₹500.00", a)
+	}
+	for _, a := range got {
+		if a.Kind == AnomalyOrder && a.Key == "regular" {
+			t.Errorf("regular order flagged: %+v", a)
+		}
+	}
+
+	if got := DetectAnomalies(orders[:5], now, 0); len(got) != 0 {
+		t.Errorf("DetectAnomalies on 5 orders = %+v, want none", got)
+	}
+}
+
+func TestDetectAnomaliesCurrentPeriod(t *testing.T) {
+	var orders []zomato.Order
+	prices := []int64{400, 450, 500, 550, 600}
+	for month := 1; month <= 12; month++ {
+		for i, price := range prices {
+			orders = append(orders, zomato.Order{
+				ID:       "regular",
+				PlacedAt: time.Date(2023, time.Month(month), 3+i*5, 20, 0, 0, 0, time.UTC),
+				Total:    rupees(price + int64(month%4)*20),
+			})
+		}
+	}
+	orders = append(orders, zomato.Order{
+		ID:       "new-year",
+		PlacedAt: time.Date(2024, 1, 2, 20, 0, 0, 0, time.UTC),
+		Total:    rupees(450),
+	})
+	now := time.Date(2024, 1, 2, 22, 0, 0, 0, time.UTC)
+
+	// January and week 1 have barely started: not unusually low.
+	for _, a := range DetectAnomalies(orders, now, 0) {
+		if a.Start.Year() == 2024 {
+			t.Errorf("in-progress period flagged: %+v", a)
+		}
+	}
+
+	// A month already far over the norm is still flagged.
+	orders[len(orders)-1].Total = rupees(20000)
+	found := false
+	for _, a := range DetectAnomalies(orders, now, 0) {
+		if a.Kind == AnomalyMonth && a.Key == "Jan 2024" && a.Score >= DefaultAnomalyThreshold {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expensive in-progress month not flagged")
+	}
+}
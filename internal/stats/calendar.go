@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// DayCount is the number of orders placed on one calendar day.
+type DayCount struct {
+	Date  time.Time // midnight in the location passed to OrdersPerDay
+	Count int
+}
+
+// OrdersPerDay counts orders per calendar day in loc, for every day from
+// start up to but not including end, days without orders included.
+func OrdersPerDay(orders []zomato.Order, start, end time.Time, loc *time.Location) []DayCount {
+	start = dayStart(start, loc)
+	counts := map[string]int{}
+	for _, o := range orders {
+		if o.PlacedAt.IsZero() {
+			continue
+		}
+		counts[o.PlacedAt.In(loc).Format("2006-01-02")]++
+	}
+	var out []DayCount
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		out = append(out, DayCount{Date: day, Count: counts[day.Format("2006-01-02")]})
+	}
+	return out
+}
+
+func dayStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestOrdersPerDay(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	orders := []zomato.Order{
+		{ID: "1", PlacedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, ist)},
+		{ID: "2", PlacedAt: time.Date(2024, 3, 1, 21, 0, 0, 0, ist)},
+		// 20:00 UTC on the 2nd is already the 3rd in IST.
+		{ID: "3", PlacedAt: time.Date(2024, 3, 2, 20, 0, 0, 0, time.UTC)},
+		{ID: "4", PlacedAt: time.Date(2024, 3, 9, 12, 0, 0, 0, ist)},
+		{ID: "5"},
+	}
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, ist)
+	days := OrdersPerDay(orders, start, time.Date(2024, 3, 5, 0, 0, 0, 0, ist), ist)
+
+	want := []int{2, 0, 1, 0}
+	if len(days) != len(want) {
+		t.Fatalf("OrdersPerDay = %+v, want %d days", days, len(want))
+	}
+	for i, w := range want {
+		date := time.Date(2024, 3, 1+i, 0, 0, 0, 0, ist)
+		if !days[i].Date.Equal(date) || days[i].Count != w {
+			t.Errorf("days[%d] = %v %d, want %v %d", i, days[i].Date, days[i].Count, date, w)
+		}
+	}
+}
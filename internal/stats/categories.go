@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// Untagged collects orders no tag matched.
+const Untagged = "untagged"
+
+// TagTotal is the orders and spend carrying one tag. An order with several
+// tags counts towards each, so totals across tags can exceed overall spend.
+type TagTotal struct {
+	Tag     string
+	Count   int
+	Total   zomato.Money
+	Percent float64 // share of all orders
+}
+
+// TagPeriod is one tag's orders and spend in one period.
+type TagPeriod struct {
+	Period string
+	Tag    string
+	Count  int
+	Total  zomato.Money
+}
+
+// byTag splits orders by tag, with untagged orders under Untagged.
+func byTag(orders []zomato.Order, tagsOf func(zomato.Order) []string) map[string][]zomato.Order {
+	out := map[string][]zomato.Order{}
+	for _, order := range orders {
+		tags := tagsOf(order)
+		if len(tags) == 0 {
+			tags = []string{Untagged}
+		}
+		for _, tag := range tags {
+			out[tag] = append(out[tag], order)
+		}
+	}
+	return out
+}
+
+// SpendByTag totals orders per tag, highest spend first. Orders should
+// share a currency.
+func SpendByTag(orders []zomato.Order, tagsOf func(zomato.Order) []string) []TagTotal {
+	var out []TagTotal
+	for tag, tagged := range byTag(orders, tagsOf) {
+		summary := ComputeSummary(tagged)
+		out = append(out, TagTotal{
+			Tag:     tag,
+			Count:   summary.Count,
+			Total:   summary.Total,
+			Percent: percent(summary.Count, len(orders)),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total.Amount != out[j].Total.Amount {
+			return out[i].Total.Amount > out[j].Total.Amount
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
+
+// SpendByTagOverTime totals orders per tag and period (see GroupOrders),
+// ordered by period and then by tag.
+func SpendByTagOverTime(orders []zomato.Order, tagsOf func(zomato.Order) []string, groupBy string) ([]TagPeriod, error) {
+	periods, err := GroupOrders(orders, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(periods))
+	for i, p := range periods {
+		rank[p.Key] = i
+	}
+	var out []TagPeriod
+	for tag, tagged := range byTag(orders, tagsOf) {
+		groups, err := GroupOrders(tagged, groupBy)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			out = append(out, TagPeriod{Period: g.Key, Tag: tag, Count: g.Count, Total: g.Total})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Period != out[j].Period {
+			return rank[out[i].Period] < rank[out[j].Period]
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out, nil
+}
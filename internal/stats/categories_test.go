@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestSpendByTag(t *testing.T) {
+	orders := []zomato.Order{
+		{ID: "1", Total: rupees(300), PlacedAt: time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC)},
+		{ID: "2", Total: rupees(100), PlacedAt: time.Date(2024, 2, 5, 20, 0, 0, 0, time.UTC)},
+		{ID: "3", Total: rupees(50), PlacedAt: time.Date(2024, 2, 6, 20, 0, 0, 0, time.UTC)},
+	}
+	tagsOf := func(o zomato.Order) []string {
+		switch o.ID {
+		case "1":
+			return []string{"biryani", "dessert"}
+		case "2":
+			return []string{"dessert"}
+		}
+		return nil
+	}
+
+	totals := SpendByTag(orders, tagsOf)
+	want := []TagTotal{
+		{Tag: "dessert", Count: 2, Total: rupees(400)},
+		{Tag: "biryani", Count: 1, Total: rupees(300)},
+		{Tag: Untagged, Count: 1, Total: rupees(50)},
+	}
+	if len(totals) != len(want) {
+		t.Fatalf("SpendByTag = %+v, want %d tags", totals, len(want))
+	}
+	for i, w := range want {
+		if totals[i].Tag != w.Tag || totals[i].Count != w.Count || totals[i].Total != w.Total {
+			t.Errorf("totals[%d] = %+v, want %+v", i, totals[i], w)
+		}
+	}
+
+	periods, err := SpendByTagOverTime(orders, tagsOf, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPeriods := []struct {
+		tag   string
+		total zomato.Money
+	}{
+		{"biryani", rupees(300)},
+		{"dessert", rupees(300)},
+		{"dessert", rupees(100)},
+		{Untagged, rupees(50)},
+	}
+	if len(periods) != len(wantPeriods) {
+		t.Fatalf("SpendByTagOverTime = %+v, want %d rows", periods, len(wantPeriods))
+	}
+	for i, w := range wantPeriods {
+		if periods[i].Tag != w.tag || periods[i].Total != w.total {
+			t.Errorf("periods[%d] = %+v, want %s %v", i, periods[i], w.tag, w.total)
+		}
+	}
+	if periods[0].Period == periods[2].Period {
+		t.Errorf("January and February share period %q", periods[0].Period)
+	}
+}
@@ -0,0 +1,174 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// CurrencyGroup holds the orders whose totals share one currency.
+type CurrencyGroup struct {
+	Currency string
+	Orders   []zomato.Order
+}
+
+// SplitByCurrency partitions orders by the currency of their totals, the
+// currency with the most orders first. Summaries and groups must be computed
+// per partition (or after ConvertOrders), since amounts in different
+// currencies can't be added.
+func SplitByCurrency(orders []zomato.Order) []CurrencyGroup {
+	index := map[string]int{}
+	var groups []CurrencyGroup
+	for _, order := range orders {
+		cur := order.Total.Currency
+		i, ok := index[cur]
+		if !ok {
+			i = len(groups)
+			index[cur] = i
+			groups = append(groups, CurrencyGroup{Currency: cur})
+		}
+		groups[i].Orders = append(groups[i].Orders, order)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Orders) != len(groups[j].Orders) {
+			return len(groups[i].Orders) > len(groups[j].Orders)
+		}
+		return groups[i].Currency < groups[j].Currency
+	})
+	return groups
+}
+
+// ComputeSummaries returns one Summary per currency, in SplitByCurrency order.
+func ComputeSummaries(orders []zomato.Order) []Summary {
+	groups := SplitByCurrency(orders)
+	out := make([]Summary, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, ComputeSummary(group.Orders))
+	}
+	return out
+}
+
+// Rates is a user-supplied table of exchange rates. Each rate is the value
+// of one unit of the currency in Base, e.g. {"base": "INR", "rates":
+// {"AED": 22.7}} means 1 AED = 22.7 INR.
+type Rates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// LoadRates reads a rates file.
+func LoadRates(path string) (Rates, error) {
+	var rates Rates
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rates, err
+	}
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return rates, fmt.Errorf("parse %s: %w", path, err)
+	}
+	rates.Base = strings.ToUpper(strings.TrimSpace(rates.Base))
+	if rates.Base == "" {
+		return rates, fmt.Errorf("%s: missing \"base\" currency", path)
+	}
+	normalized := make(map[string]float64, len(rates.Rates))
+	for code, rate := range rates.Rates {
+		if rate <= 0 {
+			return rates, fmt.Errorf("%s: rate for %s must be positive", path, code)
+		}
+		normalized[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	rates.Rates = normalized
+	return rates, nil
+}
+
+// rate returns the value of one unit of code in r.Base.
+func (r Rates) rate(code string) (float64, bool) {
+	if code == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[code]
+	return rate, ok
+}
+
+// Convert converts m into the currency to, going through r.Base when
+// neither side is the base currency.
+func (r Rates) Convert(m zomato.Money, to string) (zomato.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	from, ok := r.rate(m.Currency)
+	if !ok {
+		return zomato.Money{}, fmt.Errorf("no exchange rate for %s", m.Currency)
+	}
+	target, ok := r.rate(to)
+	if !ok {
+		return zomato.Money{}, fmt.Errorf("no exchange rate for %s", to)
+	}
+	value := m.Float() * from / target
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return zomato.Money{}, fmt.Errorf("cannot convert %s to %s", m, to)
+	}
+	return zomato.MoneyFromFloat(value, to), nil
+}
+
+// ConvertOrders returns copies of orders with every amount converted to
+// base. It fails listing all currencies that have no rate.
+func (r Rates) ConvertOrders(orders []zomato.Order, base string) ([]zomato.Order, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	missing := map[string]struct{}{}
+	convert := func(m zomato.Money) zomato.Money {
+		if m.Currency == "" || m.Currency == base {
+			return m
+		}
+		converted, err := r.Convert(m, base)
+		if err != nil {
+			missing[m.Currency] = struct{}{}
+			return m
+		}
+		return converted
+	}
+
+	out := make([]zomato.Order, len(orders))
+	for i, order := range orders {
+		order.Total = convert(order.Total)
+		if len(order.Items) > 0 {
+			items := make([]zomato.OrderItem, len(order.Items))
+			for j, item := range order.Items {
+				if !item.UnitPrice.IsZero() {
+					item.UnitPrice = convert(item.UnitPrice)
+				}
+				if !item.TotalPrice.IsZero() {
+					item.TotalPrice = convert(item.TotalPrice)
+				}
+				items[j] = item
+			}
+			order.Items = items
+		}
+		if order.Details != nil {
+			details := *order.Details
+			details.ItemTotal = convert(details.ItemTotal)
+			details.Taxes = convert(details.Taxes)
+			details.DeliveryFee = convert(details.DeliveryFee)
+			details.PackagingCharge = convert(details.PackagingCharge)
+			details.Tip = convert(details.Tip)
+			details.Discount = convert(details.Discount)
+			order.Details = &details
+		}
+		out[i] = order
+	}
+
+	if len(missing) > 0 {
+		codes := make([]string, 0, len(missing))
+		for code := range missing {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		return nil, fmt.Errorf("no exchange rate to %s for: %s", base, strings.Join(codes, ", "))
+	}
+	return out, nil
+}
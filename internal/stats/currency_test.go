@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func dirhams(major int64) zomato.Money {
+	return zomato.Money{Amount: major * 100, Currency: "AED"}
+}
+
+func TestComputeSummaries(t *testing.T) {
+	orders := []zomato.Order{
+		{ID: "1", Total: rupees(100)},
+		{ID: "2", Total: dirhams(40)},
+		{ID: "3", Total: rupees(300)},
+	}
+
+	summaries := ComputeSummaries(orders)
+	if len(summaries) != 2 {
+		t.Fatalf("ComputeSummaries returned %d summaries, want 2", len(summaries))
+	}
+	if summaries[0].Currency != "INR" || summaries[0].Total != rupees(400) || summaries[0].Average != rupees(200) {
+		t.Errorf("INR summary = %+v, want total ₹400, average ₹200", summaries[0])
+	}
+	if summaries[1].Currency != "AED" || summaries[1].Total != dirhams(40) || summaries[1].Count != 1 {
+		t.Errorf("AED summary = %+v, want 1 order totalling AED 40", summaries[1])
+	}
+}
+
+func TestRatesConvertOrders(t *testing.T) {
+	rates := Rates{Base: "INR", Rates: map[string]float64{"AED": 22.5, "USD": 83}}
+	orders := []zomato.Order{
+		{ID: "1", Total: rupees(100)},
+		{
+			ID:      "2",
+			Total:   dirhams(40),
+			Items:   []zomato.OrderItem{{Name: "Shawarma", Quantity: 1, UnitPrice: dirhams(40)}},
+			Details: &zomato.OrderDetails{DeliveryFee: dirhams(2)},
+		},
+	}
+
+	converted, err := rates.ConvertOrders(orders, "INR")
+	if err != nil {
+		t.Fatalf("ConvertOrders failed: %v", err)
+	}
+	if converted[0].Total != rupees(100) {
+		t.Errorf("INR total = %v, want unchanged", converted[0].Total)
+	}
+	if converted[1].Total != rupees(900) {
+		t.Errorf("AED total = %v, want ₹900.00", converted[1].Total)
+	}
+	if converted[1].Items[0].UnitPrice != rupees(900) || converted[1].Details.DeliveryFee != rupees(45) {
+		t.Errorf("converted item/details = %v / %v, want ₹900.00 / ₹45.00",
+			converted[1].Items[0].UnitPrice, converted[1].Details.DeliveryFee)
+	}
+	if orders[1].Total != dirhams(40) || orders[1].Details.DeliveryFee != dirhams(2) {
+		t.Errorf("ConvertOrders modified its input")
+	}
+
+	// Converting to a non-base currency goes through the base.
+	usd, err := rates.ConvertOrders(orders[:1], "usd")
+	if err != nil {
+		t.Fatalf("ConvertOrders to USD failed: %v", err)
+	}
+	if want := (zomato.Money{Amount: 120, Currency: "USD"}); usd[0].Total != want {
+		t.Errorf("USD total = %v, want %v", usd[0].Total, want)
+	}
+
+	_, err = rates.ConvertOrders([]zomato.Order{{Total: zomato.Money{Amount: 100, Currency: "SAR"}}}, "INR")
+	if err == nil || !strings.Contains(err.Error(), "SAR") {
+		t.Errorf("ConvertOrders without a SAR rate: err = %v, want missing SAR", err)
+	}
+}
+
+func TestLoadRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"base": "inr", "rates": {"aed": 22.7}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rates, err := LoadRates(path)
+	if err != nil {
+		t.Fatalf("LoadRates failed: %v", err)
+	}
+	if rates.Base != "INR" || rates.Rates["AED"] != 22.7 {
+		t.Errorf("LoadRates = %+v, want base INR with AED 22.7", rates)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"base": "INR", "rates": {"AED": 0}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRates(path); err == nil {
+		t.Error("LoadRates with a zero rate: succeeded, want error")
+	}
+}
@@ -0,0 +1,216 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// ErrNotEnoughHistory is returned when there are too few complete months
+// to forecast from.
+var ErrNotEnoughHistory = errors.New("not enough history to forecast; need at least 3 complete months")
+
+// minForecastMonths is the shortest history ForecastNextMonth accepts.
+const minForecastMonths = 3
+
+// smoothing is the exponential smoothing factor: how much each month moves
+// the level towards itself.
+const smoothing = 0.5
+
+// Period is the orders and spend of one month or week.
+type Period struct {
+	Start time.Time
+	Count int
+	Total zomato.Money
+}
+
+// Forecast projects one month's spend and order count.
+type Forecast struct {
+	Month  time.Time // first day of the forecast month
+	Orders float64
+	Spend  zomato.Money
+	// Low and High are one standard deviation of past forecast errors
+	// either side of Spend.
+	Low, High zomato.Money
+	// Seasonal is set when a month-of-year adjustment was applied, which
+	// needs two years of history.
+	Seasonal bool
+	// History is the number of complete months used.
+	History int
+}
+
+// MonthlyHistory totals orders per calendar month in loc, from the first
+// order's month up to but excluding until's month. Months without orders
+// are included with zero totals. Orders should share a currency.
+func MonthlyHistory(orders []zomato.Order, until time.Time, loc *time.Location) []Period {
+	return history(orders, monthStart(until.In(loc)), loc, monthStart, func(t time.Time) time.Time {
+		return t.AddDate(0, 1, 0)
+	})
+}
+
+// WeeklyHistory totals orders per week (starting Monday) in loc, from the
+// first order's week up to and including the last order's week.
+func WeeklyHistory(orders []zomato.Order, loc *time.Location) []Period {
+	var last time.Time
+	for _, order := range orders {
+		if order.PlacedAt.After(last) {
+			last = order.PlacedAt
+		}
+	}
+	if last.IsZero() {
+		return nil
+	}
+	end := weekStart(last.In(loc)).AddDate(0, 0, 7)
+	return history(orders, end, loc, weekStart, func(t time.Time) time.Time {
+		return t.AddDate(0, 0, 7)
+	})
+}
+
+func history(orders []zomato.Order, end time.Time, loc *time.Location, start func(time.Time) time.Time, next func(time.Time) time.Time) []Period {
+	totals := map[time.Time]*Period{}
+	var first time.Time
+	currency := ""
+	for _, order := range orders {
+		if order.PlacedAt.IsZero() {
+			continue
+		}
+		key := start(order.PlacedAt.In(loc))
+		if !key.Before(end) {
+			continue
+		}
+		if currency == "" {
+			currency = order.Total.Currency
+		}
+		p, ok := totals[key]
+		if !ok {
+			p = &Period{Start: key}
+			totals[key] = p
+		}
+		p.Count++
+		p.Total = p.Total.Add(order.Total)
+		if first.IsZero() || key.Before(first) {
+			first = key
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+	var out []Period
+	for t := first; t.Before(end); t = next(t) {
+		if p, ok := totals[t]; ok {
+			out = append(out, *p)
+			continue
+		}
+		out = append(out, Period{Start: t, Total: zomato.Money{Currency: currency}})
+	}
+	return out
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// ForecastNextMonth projects spend and order count for the month after
+// now's, from the complete months before now's. It smooths the monthly
+// series exponentially and, with two or more years of history, scales by
+// how the target month of the year usually compares to the average month.
+// Orders should share a currency.
+func ForecastNextMonth(orders []zomato.Order, now time.Time) (Forecast, error) {
+	loc := now.Location()
+	months := MonthlyHistory(orders, now, loc)
+	if len(months) < minForecastMonths {
+		return Forecast{}, ErrNotEnoughHistory
+	}
+	target := monthStart(now).AddDate(0, 1, 0)
+
+	spend := make([]float64, len(months))
+	counts := make([]float64, len(months))
+	currency := ""
+	for i, m := range months {
+		spend[i] = float64(m.Total.Amount)
+		counts[i] = float64(m.Count)
+		if m.Total.Currency != "" {
+			currency = m.Total.Currency
+		}
+	}
+
+	f := Forecast{Month: target, History: len(months)}
+	spendFactors, seasonal := seasonalFactors(months, spend)
+	countFactors, _ := seasonalFactors(months, counts)
+	f.Seasonal = seasonal
+
+	level, spread := smooth(deseasonalize(months, spend, spendFactors))
+	factor := spendFactors[target.Month()]
+	f.Spend = zomato.Money{Amount: int64(math.Round(level * factor)), Currency: currency}
+	f.Low = zomato.Money{Amount: int64(math.Round(math.Max(0, (level-spread)*factor))), Currency: currency}
+	f.High = zomato.Money{Amount: int64(math.Round((level + spread) * factor)), Currency: currency}
+
+	countLevel, _ := smooth(deseasonalize(months, counts, countFactors))
+	f.Orders = countLevel * countFactors[target.Month()]
+	return f, nil
+}
+
+// seasonalFactors returns each calendar month's mean relative to the
+// overall mean. Without two full years of history, or when the series is
+// all zero, every factor is 1.
+func seasonalFactors(months []Period, values []float64) (map[time.Month]float64, bool) {
+	factors := make(map[time.Month]float64, 12)
+	for m := time.January; m <= time.December; m++ {
+		factors[m] = 1
+	}
+	if len(months) < 24 {
+		return factors, false
+	}
+	var total float64
+	sums := map[time.Month]float64{}
+	counts := map[time.Month]int{}
+	for i, p := range months {
+		total += values[i]
+		sums[p.Start.Month()] += values[i]
+		counts[p.Start.Month()]++
+	}
+	mean := total / float64(len(values))
+	if mean == 0 {
+		return factors, false
+	}
+	for m, sum := range sums {
+		// A month of the year that was always empty would zero the
+		// forecast and divide by zero when deseasonalizing; leave it be.
+		if f := sum / float64(counts[m]) / mean; f > 0 {
+			factors[m] = f
+		}
+	}
+	return factors, true
+}
+
+func deseasonalize(months []Period, values []float64, factors map[time.Month]float64) []float64 {
+	out := make([]float64, len(values))
+	for i, p := range months {
+		out[i] = values[i] / factors[p.Start.Month()]
+	}
+	return out
+}
+
+// smooth runs simple exponential smoothing over values, returning the final
+// level and the standard deviation of the one-step-ahead errors.
+func smooth(values []float64) (level, spread float64) {
+	level = values[0]
+	var squares float64
+	for _, v := range values[1:] {
+		err := v - level
+		squares += err * err
+		level += smoothing * err
+	}
+	if n := len(values) - 1; n > 0 {
+		spread = math.Sqrt(squares / float64(n))
+	}
+	return level, spread
+}
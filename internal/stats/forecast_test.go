@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// monthly returns one order per month from start for n months, priced by
+// price(month).
+func monthly(start time.Time, n int, price func(time.Month) int64) []zomato.Order {
+	var orders []zomato.Order
+	for i := 0; i < n; i++ {
+		at := start.AddDate(0, i, 0)
+		orders = append(orders, zomato.Order{PlacedAt: at, Total: rupees(price(at.Month()))})
+	}
+	return orders
+}
+
+func TestMonthlyHistory(t *testing.T) {
+	orders := []zomato.Order{
+		{PlacedAt: time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC), Total: rupees(100)},
+		{PlacedAt: time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC), Total: rupees(50)},
+		{PlacedAt: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC), Total: rupees(200)},
+		{PlacedAt: time.Date(2024, 4, 2, 12, 0, 0, 0, time.UTC), Total: rupees(300)}, // current month
+	}
+	got := MonthlyHistory(orders, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), time.UTC)
+	want := []Period{
+		{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Count: 2, Total: rupees(150)},
+		{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Count: 0, Total: zomato.Money{Currency: "INR"}},
+		{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Count: 1, Total: rupees(200)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MonthlyHistory = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || got[i].Count != want[i].Count || got[i].Total != want[i].Total {
+			t.Errorf("month %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestForecastNextMonth(t *testing.T) {
+	now := time.Date(2024, 11, 15, 12, 0, 0, 0, time.UTC)
+
+	steady := monthly(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), 10, func(time.Month) int64 { return 1000 })
+	f, err := ForecastNextMonth(steady, now)
+	if err != nil {
+		t.Fatalf("ForecastNextMonth failed: %v", err)
+	}
+	if f.Spend != rupees(1000) || f.Low != rupees(1000) || f.High != rupees(1000) || f.Orders != 1 {
+		t.Errorf("steady forecast = %+v, want ₹1000.00 and 1 order with no spread", f)
+	}
+	if !f.Month.Equal(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)) || f.Seasonal || f.History != 10 {
+		t.Errorf("steady forecast = %v, seasonal %v, %d months; want Dec 2024, false, 10", f.Month, f.Seasonal, f.History)
+	}
+
+	// Two years in which December costs double: the December forecast
+	// should follow the season rather than the average month.
+	festive := monthly(time.Date(2022, 11, 10, 12, 0, 0, 0, time.UTC), 24, func(m time.Month) int64 {
+		if m == time.December {
+			return 2000
+		}
+		return 1000
+	})
+	f, err = ForecastNextMonth(festive, now)
+	if err != nil {
+		t.Fatalf("ForecastNextMonth failed: %v", err)
+	}
+	if !f.Seasonal || f.Spend != rupees(2000) {
+		t.Errorf("seasonal forecast = %v (seasonal %v), want ₹2000.00", f.Spend, f.Seasonal)
+	}
+
+	short := monthly(time.Date(2024, 9, 10, 12, 0, 0, 0, time.UTC), 2, func(time.Month) int64 { return 500 })
+	if _, err := ForecastNextMonth(short, now); !errors.Is(err, ErrNotEnoughHistory) {
+		t.Errorf("ForecastNextMonth with 2 months error = %v, want ErrNotEnoughHistory", err)
+	}
+}
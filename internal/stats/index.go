@@ -0,0 +1,252 @@
+package stats
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// IndexPoint is the personal food-inflation index for one period.
+type IndexPoint struct {
+	Start    time.Time
+	Label    string // "2024-01" or "2024"
+	Currency string
+	Index    float64 // 100 in the first period
+	Change   float64 // percent change from the previous period
+	Items    int     // basket items priced this period and earlier
+	CPI      float64 // reference index on the same scale; 0 when unknown
+}
+
+// PersonalInflationIndex computes a chained Laspeyres-style price index
+// over the items in orders, by month or year. The basket is everything
+// ever ordered, each restaurant's item weighted by the total quantity
+// ordered. Each period's index moves from the previous one by how much the
+// basket items priced in the period cost compared with their latest
+// earlier price, so items that come and go only count while they can be
+// compared. Periods without such items keep the previous index.
+//
+// Prices are the confident estimates from EstimatePrices, averaged per
+// period. Orders should share a currency.
+func PersonalInflationIndex(orders []zomato.Order, groupBy string) ([]IndexPoint, error) {
+	groupBy = strings.ToLower(strings.TrimSpace(groupBy))
+	if groupBy != "month" && groupBy != "year" {
+		return nil, errors.New("index group must be one of: month, year")
+	}
+
+	type priceSum struct {
+		sum float64
+		n   int
+	}
+	weights := map[string]float64{}
+	prices := map[string]map[string]*priceSum{} // period label -> item -> prices
+	var first, last time.Time
+	currency := ""
+	for _, p := range EstimatePrices(orders) {
+		if p.Confidence < MinTrendConfidence {
+			continue
+		}
+		currency = p.Currency
+		key := priceKey(p)
+		weights[key] += float64(max(p.Quantity, 1))
+		start := indexPeriod(p.Date, groupBy)
+		label := indexLabel(start, groupBy)
+		if prices[label] == nil {
+			prices[label] = map[string]*priceSum{}
+		}
+		ps := prices[label][key]
+		if ps == nil {
+			ps = &priceSum{}
+			prices[label][key] = ps
+		}
+		ps.sum += p.UnitPrice
+		ps.n++
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	if first.IsZero() {
+		return nil, nil
+	}
+
+	var out []IndexPoint
+	latest := map[string]float64{} // item -> latest price so far
+	index := 100.0
+	for start := first; !start.After(last); start = nextIndexPeriod(start, groupBy) {
+		point := IndexPoint{Start: start, Label: indexLabel(start, groupBy), Currency: currency}
+		var now, before float64
+		for key, ps := range prices[point.Label] {
+			price := ps.sum / float64(ps.n)
+			if prev, ok := latest[key]; ok {
+				now += weights[key] * price
+				before += weights[key] * prev
+				point.Items++
+			}
+			latest[key] = price
+		}
+		if before > 0 {
+			next := index * now / before
+			point.Change = math.Round((next/index-1)*10000) / 100
+			index = next
+		}
+		point.Index = math.Round(index*100) / 100
+		out = append(out, point)
+	}
+	return out, nil
+}
+
+func indexPeriod(t time.Time, groupBy string) time.Time {
+	if groupBy == "year" {
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func nextIndexPeriod(start time.Time, groupBy string) time.Time {
+	if groupBy == "year" {
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+func indexLabel(start time.Time, groupBy string) string {
+	if groupBy == "year" {
+		return start.Format("2006")
+	}
+	return start.Format("2006-01")
+}
+
+// AnnualChange is the percent change of the index, and of the CPI when
+// known for both ends, over the year to the last period.
+func AnnualChange(points []IndexPoint) (personal, cpi float64, hasCPI, ok bool) {
+	if len(points) == 0 {
+		return 0, 0, false, false
+	}
+	end := points[len(points)-1]
+	yearAgo := end.Start.AddDate(-1, 0, 0)
+	for _, p := range points {
+		if !p.Start.Equal(yearAgo) {
+			continue
+		}
+		personal = math.Round((end.Index/p.Index-1)*10000) / 100
+		if p.CPI > 0 && end.CPI > 0 {
+			cpi = math.Round((end.CPI/p.CPI-1)*10000) / 100
+			hasCPI = true
+		}
+		return personal, cpi, hasCPI, true
+	}
+	return 0, 0, false, false
+}
+
+// CPI is a reference consumer price index by month ("2006-01"), such as the
+// food CPI published by a statistics office.
+type CPI map[string]float64
+
+// LoadCPI reads a CPI series from a CSV file of date,value rows. Dates are
+// months (2024-01), days (2024-01-15, taken as their month) or years
+// (2024, used for each month of the year that has no monthly value). A
+// header row and lines starting with # are skipped.
+func LoadCPI(path string) (CPI, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	monthly := CPI{}
+	yearly := map[int]float64{}
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("%s:%d: want date,value", path, line)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("%s:%d: invalid value %q", path, line, record[1])
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("%s:%d: value must be positive", path, line)
+		}
+		date := strings.TrimSpace(record[0])
+		if t, err := time.Parse("2006", date); err == nil {
+			yearly[t.Year()] = value
+			continue
+		}
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			t, err = time.Parse("2006-01", date)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid date %q (use 2024-01, 2024-01-15 or 2024)", path, line, date)
+		}
+		monthly[t.Format("2006-01")] = value
+	}
+	for year, value := range yearly {
+		for m := time.January; m <= time.December; m++ {
+			key := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+			if _, ok := monthly[key]; !ok {
+				monthly[key] = value
+			}
+		}
+	}
+	if len(monthly) == 0 {
+		return nil, fmt.Errorf("%s: no CPI values", path)
+	}
+	return monthly, nil
+}
+
+// average is the mean CPI over the months from start until end.
+func (c CPI) average(start, end time.Time) (float64, bool) {
+	sum, n := 0.0, 0
+	for t := start; t.Before(end); t = t.AddDate(0, 1, 0) {
+		if v, ok := c[t.Format("2006-01")]; ok {
+			sum += v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// CompareCPI sets the CPI of each point, rescaled so it equals the personal
+// index in the first period both are known. groupBy must match the one the
+// points were computed with.
+func CompareCPI(points []IndexPoint, cpi CPI, groupBy string) {
+	scale := 0.0
+	for i := range points {
+		start := points[i].Start
+		v, ok := cpi.average(start, nextIndexPeriod(start, strings.ToLower(strings.TrimSpace(groupBy))))
+		if !ok {
+			continue
+		}
+		if scale == 0 {
+			scale = points[i].Index / v
+		}
+		points[i].CPI = math.Round(v*scale*100) / 100
+	}
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestPersonalInflationIndex(t *testing.T) {
+	month := func(m int) time.Time { return time.Date(2024, time.Month(m), 10, 20, 0, 0, 0, time.UTC) }
+	single := func(id string, m int, restaurant, item string, total int64) zomato.Order {
+		return zomato.Order{
+			ID: id, Restaurant: restaurant, Status: "Delivered", PlacedAt: month(m), Total: rupees(total),
+			Items: []zomato.OrderItem{{Name: item, Quantity: 1}},
+		}
+	}
+	orders := []zomato.Order{
+		single("1", 1, "Biryani House", "Biryani", 300),
+		single("2", 1, "Chai Point", "Tea", 100),
+		// Biryani up 10%; tea not ordered.
+		single("3", 2, "Biryani House", "Biryani", 330),
+		// Nothing in March.
+		// Both up: biryani 330 -> 363 (+10%), tea 100 -> 120 (+20%).
+		single("4", 4, "Biryani House", "Biryani", 363),
+		single("5", 4, "Chai Point", "Tea", 120),
+		single("6", 4, "Chai Point", "Tea", 120),
+	}
+
+	points, err := PersonalInflationIndex(orders, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Biryani weighs 3 and tea 3, so April moves by
+	// (3×363 + 3×120) / (3×330 + 3×100) = 1449/1290.
+	want := []struct {
+		label string
+		index float64
+		items int
+	}{
+		{"2024-01", 100, 0},
+		{"2024-02", 110, 1},
+		{"2024-03", 110, 0},
+		{"2024-04", 123.56, 2},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("got %d points %+v, want %d", len(points), points, len(want))
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Label != w.label || p.Index != w.index || p.Items != w.items {
+			t.Errorf("point %d = %s %.2f (%d items), want %s %.2f (%d items)", i, p.Label, p.Index, p.Items, w.label, w.index, w.items)
+		}
+	}
+	if points[1].Change != 10 {
+		t.Errorf("February change = %.2f, want 10", points[1].Change)
+	}
+
+	if _, err := PersonalInflationIndex(orders, "week"); err == nil {
+		t.Error("PersonalInflationIndex(week) succeeded, want error")
+	}
+}
+
+func TestLoadCPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpi.csv")
+	data := "date,food_cpi\n# annual value for 2023\n2023,150\n2024-01,160\n2024-02-01, 164\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cpi, err := LoadCPI(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpi["2023-06"] != 150 || cpi["2024-01"] != 160 || cpi["2024-02"] != 164 || len(cpi) != 14 {
+		t.Errorf("LoadCPI = %v", cpi)
+	}
+
+	if err := os.WriteFile(path, []byte("2024-01,160\n2024-13,170\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCPI(path); err == nil {
+		t.Error("LoadCPI with an invalid month succeeded, want error")
+	}
+}
+
+func TestCompareCPIAndAnnualChange(t *testing.T) {
+	var points []IndexPoint
+	cpi := CPI{}
+	for m := 0; m < 13; m++ {
+		start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, m, 0)
+		points = append(points, IndexPoint{Start: start, Label: start.Format("2006-01"), Index: 100 + float64(m)})
+		if m > 0 {
+			cpi[start.Format("2006-01")] = 200 + 2*float64(m)
+		}
+	}
+	CompareCPI(points, cpi, "month")
+	// Rescaled to the index in February, the first month with a CPI value.
+	if points[0].CPI != 0 || points[1].CPI != 101 || points[12].CPI != 112 {
+		t.Errorf("CPI = %.2f, %.2f, %.2f; want 0, 101, 112", points[0].CPI, points[1].CPI, points[12].CPI)
+	}
+
+	personal, cpiChange, hasCPI, ok := AnnualChange(points)
+	if !ok || personal != 12 {
+		t.Errorf("AnnualChange = %.2f, %v; want 12%%", personal, ok)
+	}
+	if hasCPI {
+		t.Errorf("AnnualChange reported a CPI change (%.2f) without a value a year back", cpiChange)
+	}
+}
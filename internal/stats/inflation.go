@@ -1,7 +1,9 @@
 package stats
 
 import (
+	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -15,9 +17,23 @@ type ItemPricePoint struct {
 	Restaurant string
 	ItemName   string
 	UnitPrice  float64
+	Currency   string
 	Quantity   int
-	OrderTotal float64
+	OrderTotal zomato.Money
 	Change     float64 // Percentage change from previous point (from same restaurant)
+	Confidence float64 // 0-1, see EstimatePrices
+	PreTax     bool    // priced from the bill, before taxes, fees and discounts
+}
+
+// priceKey is the series a price point belongs to. Prices from the bill and
+// from order totals are kept apart so syncing --details for part of the
+// history doesn't show up as a price change.
+func priceKey(p ItemPricePoint) string {
+	key := p.Restaurant + "|" + p.ItemName + "|" + p.Currency
+	if p.PreTax {
+		key += "|pre-tax"
+	}
+	return key
 }
 
 type InflationTrend struct {
@@ -27,47 +43,72 @@ type InflationTrend struct {
 	FirstSeen   time.Time
 	FirstPrice  float64
 	LastPrice   float64
+	Currency    string
 	TotalChange float64
 	Count       int
+	Confidence  float64 // average over Points
+	PreTax      bool    // prices from the bill; see ItemPricePoint
 	Points      []ItemPricePoint
 }
 
+// Trend sort orders for TrendOptions.Sort.
+const (
+	SortCount     = "count"      // most priced orders first
+	SortChange    = "change"     // biggest increase first
+	SortAbsChange = "abs-change" // biggest increase or decrease first
+	SortPrice     = "price"      // cheapest last price first
+)
+
+// TrendSorts lists the accepted TrendOptions.Sort values.
+var TrendSorts = []string{SortCount, SortChange, SortAbsChange, SortPrice}
+
+// TrendOptions selects and orders inflation trends.
+type TrendOptions struct {
+	Query     string  // item name contains, ignoring case
+	Sort      string  // one of TrendSorts; default SortCount
+	Limit     int     // 0 for all
+	MinPoints int     // priced orders a trend needs; default 2
+	MinChange float64 // percent; negative keeps drops of at least that much
+}
+
 // FindTopInflationTrends identifies distinct Restaurant+Item pairs with significant history.
 func FindTopInflationTrends(orders []zomato.Order, limit int) []InflationTrend {
-	// 1. Group all single-item orders by "Restaurant|ItemName"
-	groups := make(map[string][]ItemPricePoint)
+	trends, _ := FindInflationTrends(orders, TrendOptions{Limit: limit})
+	return trends
+}
 
-	// Sort orders oldest first
-	sort.Slice(orders, func(i, j int) bool {
-		return orders[i].PlacedAt.Before(orders[j].PlacedAt)
-	})
+// FindInflationTrends returns the price trend of each Restaurant+Item pair,
+// from its confident price estimates (see EstimatePrices).
+func FindInflationTrends(orders []zomato.Order, opts TrendOptions) ([]InflationTrend, error) {
+	if opts.Sort == "" {
+		opts.Sort = SortCount
+	}
+	if !slices.Contains(TrendSorts, opts.Sort) {
+		return nil, fmt.Errorf("sort must be one of: %s", strings.Join(TrendSorts, ", "))
+	}
+	if opts.MinPoints <= 0 {
+		opts.MinPoints = 2
+	}
+	query := strings.ToLower(strings.TrimSpace(opts.Query))
 
-	for _, order := range orders {
-		if order.Status != "Delivered" || len(order.Items) != 1 {
+	// 1. Group confident price estimates by "Restaurant|ItemName|Currency",
+	// keeping bill prices apart
+	groups := make(map[string][]ItemPricePoint)
+	for _, point := range EstimatePrices(orders) {
+		if point.Confidence < MinTrendConfidence {
 			continue
 		}
-		total, _ := parseAmount(order.Total)
-		if total <= 0 {
+		if query != "" && !strings.Contains(strings.ToLower(point.ItemName), query) {
 			continue
 		}
-		item := order.Items[0]
-		key := order.Restaurant + "|" + item.Name
-		
-		unitPrice := total / float64(max(item.Quantity, 1))
-		unitPrice = math.Round(unitPrice*100) / 100
-
-		groups[key] = append(groups[key], ItemPricePoint{
-			Date:       order.PlacedAt,
-			Restaurant: order.Restaurant,
-			ItemName:   item.Name,
-			UnitPrice:  unitPrice,
-		})
+		key := priceKey(point)
+		groups[key] = append(groups[key], point)
 	}
 
-	// 2. Convert valid groups (>= 2 points) to trends
+	// 2. Convert valid groups (enough points, enough change) to trends
 	var trends []InflationTrend
 	for _, points := range groups {
-		if len(points) < 2 {
+		if len(points) < opts.MinPoints {
 			continue
 		}
 		first := points[0]
@@ -77,6 +118,10 @@ func FindTopInflationTrends(orders []zomato.Order, limit int) []InflationTrend {
 		if first.UnitPrice > 0 {
 			change = ((last.UnitPrice - first.UnitPrice) / first.UnitPrice) * 100
 		}
+		change = math.Round(change*100) / 100
+		if opts.MinChange > 0 && change < opts.MinChange || opts.MinChange < 0 && change > opts.MinChange {
+			continue
+		}
 
 		// Shorten restaurant name to first 2 words for display
 		restParts := strings.Fields(points[0].Restaurant)
@@ -85,75 +130,68 @@ func FindTopInflationTrends(orders []zomato.Order, limit int) []InflationTrend {
 			shortRest = strings.Join(restParts[:2], " ")
 		}
 
+		key := shortRest + " - " + points[0].ItemName
+		if first.PreTax {
+			key += " (pre-tax)"
+		}
+
 		trends = append(trends, InflationTrend{
-			Key:         shortRest + " - " + points[0].ItemName,
+			Key:         key,
 			ItemName:    points[0].ItemName,
 			Restaurant:  points[0].Restaurant,
 			FirstSeen:   first.Date,
 			FirstPrice:  first.UnitPrice,
 			LastPrice:   last.UnitPrice,
-			TotalChange: math.Round(change*100) / 100,
+			Currency:    first.Currency,
+			TotalChange: change,
 			Count:       len(points),
+			Confidence:  meanConfidence(points),
+			PreTax:      first.PreTax,
 			Points:      points,
 		})
 	}
 
-	// 3. Sort by popularity (Count) descending
+	// 3. Sort, by Key when tied
+	less := map[string]func(a, b InflationTrend) bool{
+		SortCount:     func(a, b InflationTrend) bool { return a.Count > b.Count },
+		SortChange:    func(a, b InflationTrend) bool { return a.TotalChange > b.TotalChange },
+		SortAbsChange: func(a, b InflationTrend) bool { return math.Abs(a.TotalChange) > math.Abs(b.TotalChange) },
+		SortPrice:     func(a, b InflationTrend) bool { return a.LastPrice < b.LastPrice },
+	}[opts.Sort]
 	sort.Slice(trends, func(i, j int) bool {
-		return trends[i].Count > trends[j].Count
+		if less(trends[i], trends[j]) || less(trends[j], trends[i]) {
+			return less(trends[i], trends[j])
+		}
+		if trends[i].Key != trends[j].Key {
+			return trends[i].Key < trends[j].Key
+		}
+		return trends[i].Restaurant < trends[j].Restaurant
 	})
 
 	// 4. Return top N
-	if len(trends) > limit {
-		return trends[:limit]
+	if opts.Limit > 0 && len(trends) > opts.Limit {
+		return trends[:opts.Limit], nil
 	}
-	return trends
+	return trends, nil
 }
 
+// CalculateInflation returns the price history of items whose name
+// contains query, oldest first (see EstimatePrices).
 func CalculateInflation(orders []zomato.Order, query string) ([]ItemPricePoint, error) {
 	query = strings.ToLower(strings.TrimSpace(query))
 	var points []ItemPricePoint
-
-	sort.Slice(orders, func(i, j int) bool {
-		return orders[i].PlacedAt.Before(orders[j].PlacedAt)
-	})
-
-	for _, order := range orders {
-		if order.Status != "Delivered" || len(order.Items) != 1 {
-			continue
-		}
-
-		item := order.Items[0]
-		itemName := strings.ToLower(item.Name)
-
-		if query != "" && !strings.Contains(itemName, query) {
-			continue
-		}
-
-		total, _ := parseAmount(order.Total)
-		if total <= 0 {
+	for _, point := range EstimatePrices(orders) {
+		if query != "" && !strings.Contains(strings.ToLower(point.ItemName), query) {
 			continue
 		}
-		
-		unitPrice := total / float64(max(item.Quantity, 1))
-		unitPrice = math.Round(unitPrice*100) / 100
-
-		points = append(points, ItemPricePoint{
-			Date:       order.PlacedAt,
-			OrderId:    order.ID,
-			Restaurant: order.Restaurant,
-			ItemName:   item.Name,
-			UnitPrice:  unitPrice,
-			Quantity:   item.Quantity,
-			OrderTotal: total,
-		})
+		points = append(points, point)
 	}
 
-	// Calculate changes ONLY against same restaurant
+	// Calculate changes ONLY against the same item at the same restaurant (and currency and price basis)
 	lastPriceByRest := make(map[string]float64)
 
 	for i := range points {
-		rest := points[i].Restaurant
+		rest := priceKey(points[i])
 		prevPrice := lastPriceByRest[rest]
 		
 		if prevPrice > 0 {
@@ -167,6 +205,14 @@ func CalculateInflation(orders []zomato.Order, query string) ([]ItemPricePoint,
 	return points, nil
 }
 
+func meanConfidence(points []ItemPricePoint) float64 {
+	sum := 0.0
+	for _, p := range points {
+		sum += p.Confidence
+	}
+	return math.Round(sum/float64(len(points))*100) / 100
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -1,9 +1,12 @@
 package stats
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/maheshrijal/zocli/internal/names"
 	"github.com/maheshrijal/zocli/internal/zomato"
 )
 
@@ -14,15 +17,15 @@ func TestCalculateInflation(t *testing.T) {
 
 	orders := []zomato.Order{
 		// Match 1: Pizza, Price 100 @ Dominos
-		{ID: "1", Restaurant: "Dominos", PlacedAt: t1, Status: "Delivered", Total: "₹100", Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 1}}},
+		{ID: "1", Restaurant: "Dominos", PlacedAt: t1, Status: "Delivered", Total: rupees(100), Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 1}}},
 
 		// Match 2: Pizza, Price 120 @ Dominos (Inflation +20%)
-		{ID: "3", Restaurant: "Dominos", PlacedAt: t3, Status: "Delivered", Total: "₹240", Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 2}}},
+		{ID: "3", Restaurant: "Dominos", PlacedAt: t3, Status: "Delivered", Total: rupees(240), Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 2}}},
 
 		// Match 3: Pizza, Price 500 @ PizzaHut (Should NOT compare with Dominos)
 		// 100 -> 120 (Dominos)
 		// 500 (PizzaHut, New Chain) -> Change should be 0 because it's the first time seeing PizzaHut
-		{ID: "5", Restaurant: "PizzaHut", PlacedAt: t2, Status: "Delivered", Total: "₹500", Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 1}}},
+		{ID: "5", Restaurant: "PizzaHut", PlacedAt: t2, Status: "Delivered", Total: rupees(500), Items: []zomato.OrderItem{{Name: "Cheese Pizza", Quantity: 1}}},
 	}
 
 	points, err := CalculateInflation(orders, "Pizza")
@@ -65,14 +68,14 @@ func TestFindTopInflationTrends(t *testing.T) {
 
 	orders := []zomato.Order{
 		// Trend 1: Burger @ McD (2 orders) -> VALID
-		{ID: "1", Restaurant: "McD", PlacedAt: t1, Status: "Delivered", Total: "₹50", Items: []zomato.OrderItem{{Name: "Burger", Quantity: 1}}},
-		{ID: "2", Restaurant: "McD", PlacedAt: t2, Status: "Delivered", Total: "₹60", Items: []zomato.OrderItem{{Name: "Burger", Quantity: 1}}},
+		{ID: "1", Restaurant: "McD", PlacedAt: t1, Status: "Delivered", Total: rupees(50), Items: []zomato.OrderItem{{Name: "Burger", Quantity: 1}}},
+		{ID: "2", Restaurant: "McD", PlacedAt: t2, Status: "Delivered", Total: rupees(60), Items: []zomato.OrderItem{{Name: "Burger", Quantity: 1}}},
 
 		// Trend 2: Pizza @ Dominos (1 order) -> INVALID (need >=2)
-		{ID: "3", Restaurant: "Dominos", PlacedAt: t1, Status: "Delivered", Total: "₹100", Items: []zomato.OrderItem{{Name: "Pizza", Quantity: 1}}},
+		{ID: "3", Restaurant: "Dominos", PlacedAt: t1, Status: "Delivered", Total: rupees(100), Items: []zomato.OrderItem{{Name: "Pizza", Quantity: 1}}},
 		
 		// Trend 3: Pizza @ PizzaHut (1 order) -> INVALID (need >=2)
-		{ID: "4", Restaurant: "PizzaHut", PlacedAt: t1, Status: "Delivered", Total: "₹100", Items: []zomato.OrderItem{{Name: "Pizza", Quantity: 1}}},
+		{ID: "4", Restaurant: "PizzaHut", PlacedAt: t1, Status: "Delivered", Total: rupees(100), Items: []zomato.OrderItem{{Name: "Pizza", Quantity: 1}}},
 	}
 
 	trends := FindTopInflationTrends(orders, 5)
@@ -88,3 +91,93 @@ func TestFindTopInflationTrends(t *testing.T) {
 		t.Errorf("Expected 20%% change, got %f", trends[0].TotalChange)
 	}
 }
+
+func TestFindInflationTrendsOptions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+	order := func(d int, restaurant, item string, total int64) zomato.Order {
+		return zomato.Order{
+			Restaurant: restaurant, PlacedAt: day(d), Status: "Delivered", Total: rupees(total),
+			Items: []zomato.OrderItem{{Name: item, Quantity: 1}},
+		}
+	}
+	orders := []zomato.Order{
+		order(0, "McD", "Burger", 100), order(30, "McD", "Burger", 105), order(60, "McD", "Burger", 110),
+		order(0, "KFC", "Zinger Burger", 200), order(30, "KFC", "Zinger Burger", 250),
+		order(0, "Cafe", "Coffee", 150), order(30, "Cafe", "Coffee", 120),
+		order(0, "Diner", "Burger", 90),
+	}
+	keys := func(trends []InflationTrend) []string {
+		var out []string
+		for _, t := range trends {
+			out = append(out, t.Key)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		opts TrendOptions
+		want []string
+	}{
+		{"by count", TrendOptions{}, []string{"McD - Burger", "Cafe - Coffee", "KFC - Zinger Burger"}},
+		{"by change", TrendOptions{Sort: SortChange}, []string{"KFC - Zinger Burger", "McD - Burger", "Cafe - Coffee"}},
+		{"by abs change", TrendOptions{Sort: SortAbsChange, Limit: 2}, []string{"KFC - Zinger Burger", "Cafe - Coffee"}},
+		{"min change", TrendOptions{MinChange: 10}, []string{"McD - Burger", "KFC - Zinger Burger"}},
+		{"drops", TrendOptions{MinChange: -10}, []string{"Cafe - Coffee"}},
+		{"min points", TrendOptions{MinPoints: 3}, []string{"McD - Burger"}},
+		{"compare a dish", TrendOptions{Query: "burger", Sort: SortPrice, MinPoints: 1},
+			[]string{"Diner - Burger", "McD - Burger", "KFC - Zinger Burger"}},
+	}
+	for _, tt := range tests {
+		trends, err := FindInflationTrends(orders, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := keys(trends); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: trends = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := FindInflationTrends(orders, TrendOptions{Sort: "name"}); err == nil {
+		t.Error("FindInflationTrends with an unknown sort succeeded, want error")
+	}
+}
+
+func TestInflationKeepsPortionSizesApart(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+	var orders []zomato.Order
+	for i, item := range []string{"Biryani (Half)", "Biryani (Full)", "biryani (half)", "Biryani (Full)"} {
+		total := int64(200)
+		if strings.Contains(strings.ToLower(item), "full") {
+			total = 350
+		}
+		orders = append(orders, zomato.Order{
+			Restaurant: "Paradise", PlacedAt: day(i * 30), Status: "Delivered", Total: rupees(total),
+			Items: []zomato.OrderItem{{Name: item, Quantity: 1}},
+		})
+	}
+	n := names.NewNormalizer(names.Aliases{})
+	n.KeepItemQualifiers = true
+
+	trends, err := FindInflationTrends(n.Apply(orders), TrendOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]float64{}
+	for _, tr := range trends {
+		got[tr.Key] = tr.TotalChange
+	}
+	want := map[string]float64{"Paradise - Biryani (Half)": 0, "Paradise - Biryani (Full)": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trends = %v, want %v", got, want)
+	}
+
+	// Folding the sizes together turns the switch into a price change.
+	trends, err = FindInflationTrends(names.NewNormalizer(names.Aliases{}).Apply(orders), TrendOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 1 || trends[0].TotalChange == 0 {
+		t.Errorf("folded trends = %+v, want one merged trend with a change", trends)
+	}
+}
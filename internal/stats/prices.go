@@ -0,0 +1,286 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// PriceWindow is how far before and after a multi-item order other orders
+// from the same restaurant are used to split its total across its items.
+const PriceWindow = 45 * 24 * time.Hour
+
+// MinTrendConfidence is the confidence a price estimate needs to count
+// towards an inflation trend.
+const MinTrendConfidence = 0.5
+
+// EstimatePrices returns a unit price for each item of each delivered order
+// that can be priced, oldest first. Prices come from, in order of
+// preference:
+//
+//   - the item prices on the bill, for orders synced with --details
+//     (confidence 1)
+//   - the order total divided by the quantity, for single-item orders
+//     (confidence 1)
+//   - a least-squares fit of the totals of the restaurant's orders within
+//     PriceWindow, for multi-item orders
+//
+// Fitted prices are only reported for items the fit determines uniquely:
+// an order of a pizza and a coke is split once either has been ordered on
+// its own, or with a different combination. Their confidence grows with
+// the number of orders containing the item and shrinks when the orders
+// disagree (e.g. after a price change within the window). Prices from
+// totals include the order's share of taxes, fees and discounts; orders
+// with a bill are priced before them (PreTax) and only fitted against
+// other orders with a bill.
+func EstimatePrices(orders []zomato.Order) []ItemPricePoint {
+	groups := map[string][]zomato.Order{}
+	for _, order := range orders {
+		if order.Status != "Delivered" || len(order.Items) == 0 || order.Total.Amount <= 0 {
+			continue
+		}
+		key := order.Restaurant + "|" + order.Total.Currency
+		groups[key] = append(groups[key], order)
+	}
+
+	var points []ItemPricePoint
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].PlacedAt.Before(group[j].PlacedAt) })
+		for _, order := range group {
+			points = append(points, priceOrder(group, order)...)
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		if !points[i].Date.Equal(points[j].Date) {
+			return points[i].Date.Before(points[j].Date)
+		}
+		if points[i].OrderId != points[j].OrderId {
+			return points[i].OrderId < points[j].OrderId
+		}
+		return points[i].ItemName < points[j].ItemName
+	})
+	return points
+}
+
+// priceOrder prices the items of one order of group, using the orders
+// around it when it has several items.
+func priceOrder(group []zomato.Order, order zomato.Order) []ItemPricePoint {
+	point := func(item zomato.OrderItem, price, confidence float64) ItemPricePoint {
+		return ItemPricePoint{
+			Date:       order.PlacedAt,
+			OrderId:    order.ID,
+			Restaurant: order.Restaurant,
+			ItemName:   item.Name,
+			UnitPrice:  zomato.MoneyFromFloat(price, order.Total.Currency).Float(),
+			Currency:   order.Total.Currency,
+			Quantity:   item.Quantity,
+			OrderTotal: order.Total,
+			Confidence: confidence,
+			PreTax:     preTax(order),
+		}
+	}
+
+	if billed(order) {
+		var out []ItemPricePoint
+		for _, item := range order.Items {
+			out = append(out, point(item, item.UnitPrice.Float(), 1))
+		}
+		return out
+	}
+	quantities := itemQuantities(order)
+	if len(quantities) == 1 {
+		item := order.Items[0]
+		return []ItemPricePoint{point(item, orderAmount(order).Div(quantities[item.Name]).Float(), 1)}
+	}
+
+	// Least squares over the orders within the window: one equation per
+	// order (sum of quantity × price = amount), or per item for billed
+	// orders.
+	columns := map[string]int{}
+	column := func(name string) int {
+		c, ok := columns[name]
+		if !ok {
+			c = len(columns)
+			columns[name] = c
+		}
+		return c
+	}
+	type equation struct {
+		coef map[int]float64
+		b    float64
+	}
+	var eqs []equation
+	seen := map[string]int{} // item -> orders containing it
+	for _, other := range group {
+		if other.PlacedAt.Sub(order.PlacedAt).Abs() > PriceWindow || preTax(other) != preTax(order) {
+			continue
+		}
+		if billed(other) {
+			for _, item := range other.Items {
+				eqs = append(eqs, equation{coef: map[int]float64{column(item.Name): 1}, b: item.UnitPrice.Float()})
+			}
+		} else {
+			eq := equation{coef: map[int]float64{}, b: orderAmount(other).Float()}
+			for name, q := range itemQuantities(other) {
+				eq.coef[column(name)] = float64(q)
+			}
+			eqs = append(eqs, eq)
+		}
+		for name := range itemQuantities(other) {
+			seen[name]++
+		}
+	}
+	a := make([][]float64, len(eqs))
+	b := make([]float64, len(eqs))
+	for r, eq := range eqs {
+		a[r] = make([]float64, len(columns))
+		for c, v := range eq.coef {
+			a[r][c] = v
+		}
+		b[r] = eq.b
+	}
+	x, unique, fit := leastSquares(a, b)
+
+	var out []ItemPricePoint
+	done := map[string]bool{}
+	for _, item := range order.Items {
+		c := columns[item.Name]
+		if done[item.Name] || !unique[c] || x[c] <= 0 {
+			continue
+		}
+		done[item.Name] = true
+		confidence := fit * (1 - math.Pow(0.5, float64(seen[item.Name])))
+		out = append(out, point(item, x[c], math.Round(confidence*100)/100))
+	}
+	return out
+}
+
+// billed reports whether every item of the order has a price from the bill.
+func billed(order zomato.Order) bool {
+	if order.Details == nil {
+		return false
+	}
+	for _, item := range order.Items {
+		if item.UnitPrice.Amount <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// preTax reports whether the order's items are priced from its bill, before
+// taxes, fees and discounts.
+func preTax(order zomato.Order) bool {
+	return billed(order) || order.Details != nil && order.Details.ItemTotal.Amount > 0
+}
+
+// orderAmount is what the order's items cost: the bill's item total when
+// known, otherwise the order total.
+func orderAmount(order zomato.Order) zomato.Money {
+	if order.Details != nil && order.Details.ItemTotal.Amount > 0 {
+		return order.Details.ItemTotal
+	}
+	return order.Total
+}
+
+// itemQuantities sums the quantity of each distinct item name.
+func itemQuantities(order zomato.Order) map[string]int {
+	out := map[string]int{}
+	for _, item := range order.Items {
+		out[item.Name] += max(item.Quantity, 1)
+	}
+	return out
+}
+
+// leastSquares solves a·x ≈ b. It reports which components of x every
+// least-squares solution agrees on, and how well the fit matches b, from 0
+// (residuals as large as the amounts) to 1 (exact).
+func leastSquares(a [][]float64, b []float64) ([]float64, []bool, float64) {
+	n := 0
+	if len(a) > 0 {
+		n = len(a[0])
+	}
+	// Normal equations aᵀa·x = aᵀb, reduced to row echelon form.
+	m := make([][]float64, n)
+	scale := 0.0
+	for i := range m {
+		m[i] = make([]float64, n+1)
+		for r := range a {
+			for j := 0; j < n; j++ {
+				m[i][j] += a[r][i] * a[r][j]
+			}
+			m[i][n] += a[r][i] * b[r]
+		}
+		for j := 0; j < n; j++ {
+			scale = math.Max(scale, math.Abs(m[i][j]))
+		}
+	}
+	tol := 1e-9 * math.Max(scale, 1)
+
+	pivotRow := make([]int, n) // column -> row, or -1 for free columns
+	row := 0
+	for col := 0; col < n; col++ {
+		pivotRow[col] = -1
+		best := row
+		for r := row; r < n; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[best][col]) {
+				best = r
+			}
+		}
+		if row >= n || math.Abs(m[best][col]) <= tol {
+			continue
+		}
+		m[row], m[best] = m[best], m[row]
+		p := m[row][col]
+		for j := col; j <= n; j++ {
+			m[row][j] /= p
+		}
+		for r := 0; r < n; r++ {
+			if r == row || m[r][col] == 0 {
+				continue
+			}
+			f := m[r][col]
+			for j := col; j <= n; j++ {
+				m[r][j] -= f * m[row][j]
+			}
+		}
+		pivotRow[col] = row
+		row++
+	}
+
+	// Free columns set to zero give one solution; a pivot column is unique
+	// when no free column feeds into it.
+	x := make([]float64, n)
+	unique := make([]bool, n)
+	for col, r := range pivotRow {
+		if r < 0 {
+			continue
+		}
+		x[col] = m[r][n]
+		unique[col] = true
+		for free, fr := range pivotRow {
+			if fr < 0 && math.Abs(m[r][free]) > 1e-9 {
+				unique[col] = false
+				break
+			}
+		}
+	}
+
+	var sumSq, sumAbs float64
+	for r := range a {
+		got := 0.0
+		for j, v := range a[r] {
+			got += v * x[j]
+		}
+		sumSq += (got - b[r]) * (got - b[r])
+		sumAbs += math.Abs(b[r])
+	}
+	fit := 1.0
+	if len(a) > 0 && sumAbs > 0 {
+		rms := math.Sqrt(sumSq / float64(len(a)))
+		fit = math.Max(0, 1-rms/(sumAbs/float64(len(a))))
+	}
+	return x, unique, fit
+}
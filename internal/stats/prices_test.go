@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func pizzeriaOrder(id string, day int, total int64, items ...string) zomato.Order {
+	o := zomato.Order{
+		ID:         id,
+		Restaurant: "Pizzeria",
+		Status:     "Delivered",
+		PlacedAt:   time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC).AddDate(0, 0, day),
+		Total:      rupees(total),
+	}
+	for _, name := range items {
+		o.Items = append(o.Items, zomato.OrderItem{Name: name, Quantity: 1})
+	}
+	return o
+}
+
+func TestEstimatePrices(t *testing.T) {
+	type price struct {
+		order, item string
+		price       float64
+		confidence  float64
+	}
+	tests := []struct {
+		name   string
+		orders []zomato.Order
+		want   []price
+	}{
+		{
+			name: "split by a single-item order",
+			orders: []zomato.Order{
+				pizzeriaOrder("1", 0, 200, "Pizza"),
+				pizzeriaOrder("2", 10, 260, "Pizza", "Coke"),
+			},
+			want: []price{{"1", "Pizza", 200, 1}, {"2", "Coke", 60, 0.5}, {"2", "Pizza", 200, 0.75}},
+		},
+		{
+			name: "split by different combinations",
+			orders: []zomato.Order{
+				pizzeriaOrder("1", 0, 260, "Pizza", "Coke"),
+				pizzeriaOrder("2", 5, 320, "Pizza", "Coke", "Coke"),
+			},
+			want: []price{{"1", "Coke", 60, 0.75}, {"1", "Pizza", 200, 0.75}, {"2", "Coke", 60, 0.75}, {"2", "Pizza", 200, 0.75}},
+		},
+		{
+			name: "same combination can't be split",
+			orders: []zomato.Order{
+				pizzeriaOrder("1", 0, 260, "Pizza", "Coke"),
+				pizzeriaOrder("2", 5, 260, "Pizza", "Coke"),
+			},
+		},
+		{
+			name: "orders outside the window are ignored",
+			orders: []zomato.Order{
+				pizzeriaOrder("1", 0, 200, "Pizza"),
+				pizzeriaOrder("2", 100, 260, "Pizza", "Coke"),
+			},
+			want: []price{{"1", "Pizza", 200, 1}},
+		},
+	}
+	for _, tt := range tests {
+		got := EstimatePrices(tt.orders)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d prices %+v, want %d", tt.name, len(got), got, len(tt.want))
+			continue
+		}
+		for i, w := range tt.want {
+			g := got[i]
+			if g.OrderId != w.order || g.ItemName != w.item || g.UnitPrice != w.price || g.Confidence != w.confidence {
+				t.Errorf("%s: price %d = %s %s %.2f (%.2f), want %s %s %.2f (%.2f)", tt.name, i,
+					g.OrderId, g.ItemName, g.UnitPrice, g.Confidence, w.order, w.item, w.price, w.confidence)
+			}
+		}
+	}
+}
+
+func TestEstimatePricesBilled(t *testing.T) {
+	o := pizzeriaOrder("1", 0, 300, "Pizza", "Coke")
+	o.Details = &zomato.OrderDetails{ItemTotal: rupees(250)}
+	o.Items[0].UnitPrice = rupees(190)
+	o.Items[1].UnitPrice = rupees(60)
+	got := EstimatePrices([]zomato.Order{o})
+	if len(got) != 2 || got[0].UnitPrice != 60 || got[1].UnitPrice != 190 || got[1].Confidence != 1 {
+		t.Errorf("EstimatePrices(billed) = %+v, want the bill's prices", got)
+	}
+}
+
+func TestEstimatePricesDisagreement(t *testing.T) {
+	pizzaConfidence := func(third int64) float64 {
+		orders := []zomato.Order{
+			pizzeriaOrder("1", 0, 200, "Pizza"),
+			pizzeriaOrder("2", 10, 260, "Pizza", "Coke"),
+			pizzeriaOrder("3", 20, third, "Pizza"),
+		}
+		for _, p := range EstimatePrices(orders) {
+			if p.OrderId == "2" && p.ItemName == "Pizza" {
+				return p.Confidence
+			}
+		}
+		t.Fatalf("no estimate for the pizza in order 2 (third pizza at %d)", third)
+		return 0
+	}
+	// The pizza's price changing within the window makes the fit worse.
+	if consistent, changed := pizzaConfidence(200), pizzaConfidence(260); changed >= consistent {
+		t.Errorf("confidence after a price change = %.2f, want below %.2f", changed, consistent)
+	}
+}
+
+func TestLeastSquaresPartial(t *testing.T) {
+	// a + b = 3 and c = 5: only c is determined.
+	x, unique, fit := leastSquares([][]float64{{1, 1, 0}, {0, 0, 1}}, []float64{3, 5})
+	if unique[0] || unique[1] || !unique[2] {
+		t.Errorf("unique = %v, want [false false true]", unique)
+	}
+	if x[2] != 5 || fit != 1 {
+		t.Errorf("x = %v, fit = %v; want c = 5 and an exact fit", x, fit)
+	}
+}
+
+func TestEstimatePricesKeepsBasesApart(t *testing.T) {
+	billedPizza := func(id string, day int) zomato.Order {
+		o := pizzeriaOrder(id, day, 220, "Pizza")
+		o.Details = &zomato.OrderDetails{ItemTotal: rupees(190)}
+		o.Items[0].UnitPrice = rupees(190)
+		return o
+	}
+	// The same ₹220 pizza, synced with --details from the third order on.
+	orders := []zomato.Order{
+		pizzeriaOrder("1", 0, 220, "Pizza"),
+		pizzeriaOrder("2", 31, 220, "Pizza"),
+		billedPizza("3", 62),
+		billedPizza("4", 93),
+	}
+
+	trends, err := FindInflationTrends(orders, TrendOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{"Pizzeria - Pizza": 220, "Pizzeria - Pizza (pre-tax)": 190}
+	if len(trends) != len(want) {
+		t.Fatalf("FindInflationTrends = %+v, want %d trends", trends, len(want))
+	}
+	for _, tr := range trends {
+		if price, ok := want[tr.Key]; !ok || tr.LastPrice != price || tr.TotalChange != 0 {
+			t.Errorf("trend %q = %.2f (%+.1f%%), want %.2f unchanged", tr.Key, tr.LastPrice, tr.TotalChange, price)
+		}
+	}
+
+	points, err := PersonalInflationIndex(orders, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range points {
+		if p.Change != 0 {
+			t.Errorf("index %s changed %+.2f%% at the switch to bill prices, want 0", p.Label, p.Change)
+		}
+	}
+}
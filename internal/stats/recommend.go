@@ -0,0 +1,274 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+const (
+	// tasteHalfLife is how fast old orders stop counting as a preference:
+	// an order this old weighs half as much as one placed today.
+	tasteHalfLife = 90 * 24 * time.Hour
+	// freshDays is how long a restaurant stays "just had that": its score
+	// recovers to 1-1/e of normal this many days after the last order.
+	freshDays = 7.0
+	// NewMaxOrders is the most orders a restaurant can have for --new.
+	NewMaxOrders = 2
+)
+
+// RecommendOptions tunes Recommend. The zero value, apart from Now,
+// recommends from every restaurant.
+type RecommendOptions struct {
+	Now time.Time
+	// Count is how many candidates to return (default 3).
+	Count int
+	// ExcludeSince drops restaurants ordered from at or after this time.
+	ExcludeSince time.Time
+	// Budget drops restaurants whose average order, in Budget's
+	// currency, costs more.
+	Budget zomato.Money
+	// New recommends places ordered from at most NewMaxOrders times,
+	// rarest first, instead of favorites.
+	New bool
+	// Rand picks among close candidates; nil uses a time-seeded source.
+	Rand *rand.Rand
+}
+
+// Recommendation is a suggested restaurant and dish, with why it was
+// picked.
+type Recommendation struct {
+	Restaurant  string
+	Dish        string
+	Score       float64
+	Orders      int
+	LastOrdered time.Time
+	Typical     zomato.Money // average order total
+	Reasons     []string
+}
+
+type candidate struct {
+	name    string
+	orders  []zomato.Order
+	last    time.Time
+	lastCur string  // currency of the latest order
+	taste   float64 // recency-weighted order count
+	dishes  map[string]float64
+	typical zomato.Money
+}
+
+// Recommend scores each restaurant in the order history and returns the
+// best candidates. A restaurant scores higher the more often it was
+// ordered (recent orders counting more), lower the more recently it was
+// last ordered, and higher when it is usually ordered at Now's time of day
+// and weekday. Scores are jittered so repeated runs vary; seed Rand for a
+// reproducible pick.
+func Recommend(orders []zomato.Order, opts RecommendOptions) ([]Recommendation, error) {
+	if len(orders) == 0 {
+		return nil, errors.New("no order history to suggest from")
+	}
+	if opts.Count <= 0 {
+		opts.Count = 3
+	}
+	rng := opts.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	byName := map[string]*candidate{}
+	for _, o := range orders {
+		name := strings.TrimSpace(o.Restaurant)
+		if name == "" || o.PlacedAt.IsZero() {
+			continue
+		}
+		c, ok := byName[name]
+		if !ok {
+			c = &candidate{name: name, dishes: map[string]float64{}}
+			byName[name] = c
+		}
+		c.orders = append(c.orders, o)
+		if o.PlacedAt.After(c.last) {
+			c.last, c.lastCur = o.PlacedAt, o.Total.Currency
+		}
+		weight := decay(opts.Now.Sub(o.PlacedAt))
+		c.taste += weight
+		for _, item := range o.Items {
+			if dish := strings.TrimSpace(item.Name); dish != "" {
+				c.dishes[dish] += weight * float64(max(item.Quantity, 1))
+			}
+		}
+	}
+	if len(byName) == 0 {
+		return nil, errors.New("no valid restaurants found")
+	}
+
+	// Score in a fixed order so a seeded source gives the same result.
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	window := currentWindow(opts.Now)
+	var out []Recommendation
+	for _, name := range names {
+		c := byName[name]
+		if !opts.ExcludeSince.IsZero() && !c.last.Before(opts.ExcludeSince) {
+			continue
+		}
+		if opts.New && len(c.orders) > NewMaxOrders {
+			continue
+		}
+		currency := c.lastCur
+		if !opts.Budget.IsZero() {
+			currency = opts.Budget.Currency
+		}
+		c.typical = averageTotal(c.orders, currency)
+		if !opts.Budget.IsZero() && (c.typical.IsZero() || c.typical.Amount > opts.Budget.Amount) {
+			continue
+		}
+
+		windowShare, dayShare := contextShares(c.orders, window, opts.Now.Weekday())
+		days := opts.Now.Sub(c.last).Hours() / 24
+		freshness := 1 - math.Exp(-math.Max(days, 0)/freshDays)
+		preference := c.taste
+		if opts.New {
+			preference = 1 / float64(len(c.orders))
+		}
+		score := preference * freshness * (1 + windowShare + dayShare/2)
+		score *= 0.75 + rng.Float64()/2
+
+		out = append(out, Recommendation{
+			Restaurant:  c.name,
+			Dish:        pickDish(c.dishes, rng),
+			Score:       score,
+			Orders:      len(c.orders),
+			LastOrdered: c.last,
+			Typical:     c.typical,
+			Reasons:     reasons(c, opts, window, windowShare, dayShare),
+		})
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no restaurant matches the suggestion filters")
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > opts.Count {
+		out = out[:opts.Count]
+	}
+	return out, nil
+}
+
+func decay(age time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(tasteHalfLife))
+}
+
+func currentWindow(now time.Time) TimeWindow {
+	for _, w := range TimeWindows {
+		if w.Contains(now) {
+			return w
+		}
+	}
+	return TimeWindows[0]
+}
+
+// contextShares is the fraction of orders placed in window and on day.
+func contextShares(orders []zomato.Order, window TimeWindow, day time.Weekday) (float64, float64) {
+	inWindow, onDay := 0, 0
+	for _, o := range orders {
+		if window.Contains(o.PlacedAt) {
+			inWindow++
+		}
+		if o.PlacedAt.Weekday() == day {
+			onDay++
+		}
+	}
+	n := float64(len(orders))
+	return float64(inWindow) / n, float64(onDay) / n
+}
+
+func averageTotal(orders []zomato.Order, currency string) zomato.Money {
+	total := zomato.Money{Currency: currency}
+	n := 0
+	for _, o := range orders {
+		if o.Total.Currency == currency {
+			total = total.Add(o.Total)
+			n++
+		}
+	}
+	if n == 0 {
+		return zomato.Money{}
+	}
+	return total.Div(n)
+}
+
+// pickDish picks a dish at random, weighted by how much of it was ordered
+// and how recently, so the favorite comes up most but not every time.
+func pickDish(dishes map[string]float64, rng *rand.Rand) string {
+	names := make([]string, 0, len(dishes))
+	sum := 0.0
+	for name, weight := range dishes {
+		names = append(names, name)
+		sum += weight
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	r := rng.Float64() * sum
+	for _, name := range names {
+		r -= dishes[name]
+		if r < 0 {
+			return name
+		}
+	}
+	return names[len(names)-1]
+}
+
+func reasons(c *candidate, opts RecommendOptions, window TimeWindow, windowShare, dayShare float64) []string {
+	var out []string
+	switch n := len(c.orders); {
+	case n == 1:
+		out = append(out, "ordered once")
+	case opts.New:
+		out = append(out, fmt.Sprintf("only ordered %d times", n))
+	default:
+		out = append(out, fmt.Sprintf("ordered %d times", n))
+	}
+	if len(c.orders) > 1 && windowShare >= 0.5 {
+		out = append(out, "usually ordered in the "+strings.ReplaceAll(window.Slug, "-", " "))
+	}
+	if len(c.orders) > 1 && dayShare >= 0.3 {
+		out = append(out, "often on "+opts.Now.Weekday().String()+"s")
+	}
+	out = append(out, "last ordered "+ago(opts.Now.Sub(c.last)))
+	if !opts.Budget.IsZero() {
+		out = append(out, fmt.Sprintf("about %s an order", c.typical))
+	}
+	return out
+}
+
+func ago(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	case days < 730:
+		return fmt.Sprintf("%d months ago", days/30)
+	}
+	return fmt.Sprintf("%d years ago", days/365)
+}
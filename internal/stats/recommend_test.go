@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func recommendFixture(now time.Time) []zomato.Order {
+	var orders []zomato.Order
+	add := func(restaurant string, total int64, daysAgo ...int) {
+		for _, d := range daysAgo {
+			orders = append(orders, zomato.Order{
+				Restaurant: restaurant,
+				Total:      rupees(total),
+				PlacedAt:   now.AddDate(0, 0, -d),
+				Items:      []zomato.OrderItem{{Name: restaurant + " special", Quantity: 1}},
+			})
+		}
+	}
+	add("Biryani House", 500, 1, 8, 15, 22, 29, 36)
+	add("Dosa Corner", 250, 10, 20, 30, 40)
+	add("Pizza Place", 450, 12, 60)
+	add("Sushi Bar", 1200, 90)
+	return orders
+}
+
+func restaurantsOf(recs []Recommendation) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = r.Restaurant
+	}
+	return out
+}
+
+func TestRecommend(t *testing.T) {
+	now := time.Date(2025, 3, 14, 20, 0, 0, 0, time.UTC)
+	orders := recommendFixture(now)
+	opts := func() RecommendOptions {
+		return RecommendOptions{Now: now, Count: 4, Rand: rand.New(rand.NewSource(1))}
+	}
+
+	recs, err := Recommend(orders, opts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Biryani House is the favorite but was ordered yesterday.
+	if recs[0].Restaurant != "Dosa Corner" {
+		t.Errorf("top pick = %q, want Dosa Corner", recs[0].Restaurant)
+	}
+	if got := restaurantsOf(recs)[3]; got != "Biryani House" && got != "Sushi Bar" {
+		t.Errorf("last pick = %q, want Biryani House or Sushi Bar", got)
+	}
+	again, _ := Recommend(orders, opts())
+	if !reflect.DeepEqual(recs, again) {
+		t.Error("Recommend with the same seed gave different results")
+	}
+	if recs[0].Dish != "Dosa Corner special" || len(recs[0].Reasons) == 0 {
+		t.Errorf("top pick = %+v, want a dish and reasons", recs[0])
+	}
+
+	o := opts()
+	o.ExcludeSince = now.AddDate(0, 0, -11)
+	o.Budget = rupees(500)
+	recs, err = Recommend(orders, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restaurantsOf(recs); !reflect.DeepEqual(got, []string{"Pizza Place"}) {
+		t.Errorf("with exclusion and budget = %v, want [Pizza Place]", got)
+	}
+
+	o = opts()
+	o.New = true
+	recs, err = Recommend(orders, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restaurantsOf(recs); len(got) != 2 || got[0] == "Dosa Corner" || got[1] == "Dosa Corner" {
+		t.Errorf("new places = %v, want Pizza Place and Sushi Bar", got)
+	}
+
+	o = opts()
+	o.Budget = rupees(100)
+	if _, err := Recommend(orders, o); err == nil {
+		t.Error("Recommend with nothing under budget succeeded, want error")
+	}
+	if _, err := Recommend(nil, opts()); err == nil {
+		t.Error("Recommend(nil) succeeded, want error")
+	}
+}
+
+func TestRecommendTimeOfDay(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC) // a Friday morning
+	var orders []zomato.Order
+	for d := 7; d <= 70; d += 7 {
+		orders = append(orders,
+			zomato.Order{Restaurant: "Breakfast Club", Total: rupees(200), PlacedAt: now.AddDate(0, 0, -d)},
+			zomato.Order{Restaurant: "Night Owl", Total: rupees(200), PlacedAt: now.AddDate(0, 0, -d).Add(13 * time.Hour)},
+		)
+	}
+	recs, err := Recommend(orders, RecommendOptions{Now: now, Count: 2, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].Restaurant != "Breakfast Club" {
+		t.Errorf("top pick = %q, want Breakfast Club", recs[0].Restaurant)
+	}
+	want := []string{"ordered 10 times", "usually ordered in the morning", "often on Fridays", "last ordered 7 days ago"}
+	if !reflect.DeepEqual(recs[0].Reasons, want) {
+		t.Errorf("reasons = %q, want %q", recs[0].Reasons, want)
+	}
+}
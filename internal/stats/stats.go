@@ -2,94 +2,13 @@ package stats
 
 import (
 	"errors"
-	"math/rand"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/maheshrijal/zocli/internal/zomato"
 )
 
-// SuggestRestaurant recommends a restaurant and a dish based on frequency.
-func SuggestRestaurant(orders []zomato.Order) (string, string, error) {
-	if len(orders) == 0 {
-		return "", "", errors.New("no order history to suggest from")
-	}
-
-	// 1. Build frequency map for restaurants
-	resCounts := make(map[string]int)
-	// Map restaurant -> item -> count
-	resItems := make(map[string]map[string]int)
-
-	for _, o := range orders {
-		resName := strings.TrimSpace(o.Restaurant)
-		if resName == "" {
-			continue
-		}
-		resCounts[resName]++
-		
-		if resItems[resName] == nil {
-			resItems[resName] = make(map[string]int)
-		}
-		for _, item := range o.Items {
-			itemName := strings.TrimSpace(item.Name)
-			if itemName != "" {
-				resItems[resName][itemName] += item.Quantity
-			}
-		}
-	}
-
-	if len(resCounts) == 0 {
-		return "", "", errors.New("no valid restaurants found")
-	}
-
-	// 2. Weighted random selection for Restaurant
-	var choices []string
-	for name, count := range resCounts {
-		for i := 0; i < count; i++ {
-			choices = append(choices, name)
-		}
-	}
-
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	chosenRes := choices[rng.Intn(len(choices))]
-
-	// 3. Pick best item from that restaurant
-	items := resItems[chosenRes]
-	var bestItem string
-	var maxCount int
-	
-	// Create weighted list for items too, to add variety?
-	// Or just pick the favorite. Let's pick the favorite for now, maybe with a fallback.
-	var itemChoices []string
-	for name, count := range items {
-		if count > maxCount {
-			maxCount = count
-			bestItem = name
-		}
-		// Also build weight list just in case we want to be random later
-		for i := 0; i < count; i++ {
-			itemChoices = append(itemChoices, name)
-		}
-	}
-	
-	// If we have items, strictly picking the top one is "safe", 
-	// but random from top items might be more fun. 
-	// Let's stick to the "Most Ordered" item for that restaurant to be helpful.
-	if bestItem == "" && len(items) > 0 {
-		// Fallback to random key if counts are all 0/weird
-		for k := range items {
-			bestItem = k
-			break
-		}
-	}
-
-	return chosenRes, bestItem, nil
-}
-
 // FilterOrdersByDate filters orders within a specific date range [start, end).
 // The start date is inclusive, the end date is exclusive.
 func FilterOrdersByDate(orders []zomato.Order, start, end time.Time) []zomato.Order {
@@ -106,14 +25,13 @@ func FilterOrdersByDate(orders []zomato.Order, start, end time.Time) []zomato.Or
 	return filtered
 }
 
-func FindMostExpensiveOrder(orders []zomato.Order) (zomato.Order, float64) {
+func FindMostExpensiveOrder(orders []zomato.Order) (zomato.Order, zomato.Money) {
 	var maxOrder zomato.Order
-	var maxAmount float64
+	var maxAmount zomato.Money
 	
 	for _, o := range orders {
-		amount, _ := parseAmount(o.Total)
-		if amount > maxAmount {
-			maxAmount = amount
+		if o.Total.Amount > maxAmount.Amount {
+			maxAmount = o.Total
 			maxOrder = o
 		}
 	}
@@ -123,9 +41,10 @@ func FindMostExpensiveOrder(orders []zomato.Order) (zomato.Order, float64) {
 
 
 type Summary struct {
-	Count    int
-	Total    float64
-	Average  float64
+	Count   int
+	Total   zomato.Money
+	Average zomato.Money
+	// Currency is the ISO code the orders share, or "" when they don't.
 	Currency string
 	Earliest time.Time
 	Latest   time.Time
@@ -134,8 +53,8 @@ type Summary struct {
 type Group struct {
 	Key     string
 	Count   int
-	Total   float64
-	Average float64
+	Total   zomato.Money
+	Average zomato.Money
 }
 
 type Bucket struct {
@@ -147,21 +66,28 @@ type Bucket struct {
 type SpendBucket struct {
 	Key     string
 	Count   int
-	Total   float64
-	Average float64
+	Total   zomato.Money
+	Average zomato.Money
 }
 
+// ComputeSummary counts and totals orders. Total, Average and Currency are
+// only set when the orders share a currency; use ComputeSummaries for
+// orders in several.
 func ComputeSummary(orders []zomato.Order) Summary {
-	var total float64
+	var total zomato.Money
 	var currency string
 	var earliest time.Time
 	var latest time.Time
+	mixed := false
 
 	for _, order := range orders {
-		amount, cur := parseAmount(order.Total)
-		total += amount
-		if currency == "" && cur != "" {
-			currency = cur
+		sum, err := total.CheckedAdd(order.Total)
+		if err != nil {
+			mixed = true
+		}
+		total = sum
+		if currency == "" && order.Total.Currency != "" {
+			currency = order.Total.Currency
 		}
 		if !order.PlacedAt.IsZero() {
 			if earliest.IsZero() || order.PlacedAt.Before(earliest) {
@@ -173,9 +99,11 @@ func ComputeSummary(orders []zomato.Order) Summary {
 		}
 	}
 
-	var avg float64
-	if len(orders) > 0 {
-		avg = total / float64(len(orders))
+	var avg zomato.Money
+	if mixed {
+		total, currency = zomato.Money{}, ""
+	} else if len(orders) > 0 {
+		avg = total.Div(len(orders))
 	}
 
 	return Summary{
@@ -188,6 +116,8 @@ func ComputeSummary(orders []zomato.Order) Summary {
 	}
 }
 
+// GroupOrders counts and totals orders per period: none, month or year.
+// Orders should share a currency.
 func GroupOrders(orders []zomato.Order, groupBy string) ([]Group, error) {
 	groupBy = strings.ToLower(strings.TrimSpace(groupBy))
 	if groupBy == "" {
@@ -205,20 +135,19 @@ func GroupOrders(orders []zomato.Order, groupBy string) ([]Group, error) {
 	groups := map[string]*Group{}
 	for _, order := range orders {
 		key := groupKey(order.PlacedAt, groupBy)
-		amount, _ := parseAmount(order.Total)
 		entry, ok := groups[key]
 		if !ok {
 			entry = &Group{Key: key}
 			groups[key] = entry
 		}
 		entry.Count++
-		entry.Total += amount
+		entry.Total = entry.Total.Add(order.Total)
 	}
 
 	out := make([]Group, 0, len(groups))
 	for _, entry := range groups {
 		if entry.Count > 0 {
-			entry.Average = entry.Total / float64(entry.Count)
+			entry.Average = entry.Total.Div(entry.Count)
 		}
 		out = append(out, *entry)
 	}
@@ -257,35 +186,43 @@ func OrdersByWeekday(orders []zomato.Order) []Bucket {
 	return out
 }
 
+// TimeWindow is a part of the day, by the hour an order was placed in.
+type TimeWindow struct {
+	Slug  string // used for budget categories, e.g. "late-night"
+	Label string
+	Start int
+	End   int
+}
+
+var TimeWindows = []TimeWindow{
+	{Slug: "late-night", Label: "Late night (00-05)", Start: 0, End: 6},
+	{Slug: "morning", Label: "Morning (06-11)", Start: 6, End: 12},
+	{Slug: "afternoon", Label: "Afternoon (12-17)", Start: 12, End: 18},
+	{Slug: "evening", Label: "Evening (18-23)", Start: 18, End: 24},
+}
+
+// Contains reports whether t falls within the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	return t.Hour() >= w.Start && t.Hour() < w.End
+}
+
 func OrdersByTimeWindow(orders []zomato.Order) []Bucket {
-	type window struct {
-		Label string
-		Start int
-		End   int
-	}
-	windows := []window{
-		{Label: "Late night (00-05)", Start: 0, End: 6},
-		{Label: "Morning (06-11)", Start: 6, End: 12},
-		{Label: "Afternoon (12-17)", Start: 12, End: 18},
-		{Label: "Evening (18-23)", Start: 18, End: 24},
-	}
-	counts := make([]int, len(windows))
+	counts := make([]int, len(TimeWindows))
 	total := 0
 	for _, order := range orders {
 		if order.PlacedAt.IsZero() {
 			continue
 		}
-		hour := order.PlacedAt.Hour()
-		for i, win := range windows {
-			if hour >= win.Start && hour < win.End {
+		for i, win := range TimeWindows {
+			if win.Contains(order.PlacedAt) {
 				counts[i]++
 				total++
 				break
 			}
 		}
 	}
-	out := make([]Bucket, 0, len(windows))
-	for i, win := range windows {
+	out := make([]Bucket, 0, len(TimeWindows))
+	for i, win := range TimeWindows {
 		out = append(out, Bucket{
 			Key:     win.Label,
 			Count:   counts[i],
@@ -295,13 +232,18 @@ func OrdersByTimeWindow(orders []zomato.Order) []Bucket {
 	return out
 }
 
+// SpendByWeekday totals orders per weekday. Orders should share a currency.
 func SpendByWeekday(orders []zomato.Order) []SpendBucket {
 	buckets := make(map[time.Weekday]*SpendBucket)
+	// Empty days still show the currency of the orders around them.
+	var zero zomato.Money
 	for _, order := range orders {
+		if zero.Currency == "" {
+			zero.Currency = order.Total.Currency
+		}
 		if order.PlacedAt.IsZero() {
 			continue
 		}
-		amount, _ := parseAmount(order.Total)
 		day := order.PlacedAt.Weekday()
 		entry, ok := buckets[day]
 		if !ok {
@@ -309,7 +251,7 @@ func SpendByWeekday(orders []zomato.Order) []SpendBucket {
 			buckets[day] = entry
 		}
 		entry.Count++
-		entry.Total += amount
+		entry.Total = entry.Total.Add(order.Total)
 	}
 
 	out := make([]SpendBucket, 0, 7)
@@ -324,10 +266,10 @@ func SpendByWeekday(orders []zomato.Order) []SpendBucket {
 	} {
 		entry := buckets[day]
 		if entry == nil {
-			entry = &SpendBucket{Key: day.String()}
+			entry = &SpendBucket{Key: day.String(), Total: zero, Average: zero}
 		}
 		if entry.Count > 0 {
-			entry.Average = entry.Total / float64(entry.Count)
+			entry.Average = entry.Total.Div(entry.Count)
 		}
 		out = append(out, *entry)
 	}
@@ -456,70 +398,3 @@ func percent(value, total int) float64 {
 	}
 	return (float64(value) / float64(total)) * 100
 }
-
-var (
-	amountPattern = regexp.MustCompile(`[0-9]+(?:[.,][0-9]+)?`)
-	moneyPattern  = regexp.MustCompile(`^\s*([^\d\s]+)?\s*([\d.,]+)\s*([^\d\s]+)?\s*$`)
-)
-
-func parseAmount(input string) (float64, string) {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return 0, ""
-	}
-
-	currency := ""
-	matches := moneyPattern.FindStringSubmatch(input)
-	if len(matches) == 4 {
-		prefix := strings.TrimSpace(matches[1])
-		suffix := strings.TrimSpace(matches[3])
-		if prefix != "" {
-			currency = prefix
-		} else if suffix != "" {
-			currency = suffix
-		}
-		value, cur := parseAmountValue(matches[2], currency)
-		return value, cur
-	}
-
-	var curBuilder strings.Builder
-	for _, r := range input {
-		if unicode.IsDigit(r) || r == '.' || r == ',' || unicode.IsSpace(r) {
-			continue
-		}
-		curBuilder.WriteRune(r)
-	}
-	currency = curBuilder.String()
-	amount := amountPattern.FindString(input)
-	if amount == "" {
-		return 0, ""
-	}
-	value, cur := parseAmountValue(amount, currency)
-	return value, cur
-}
-
-func parseAmountValue(amountRaw, currency string) (float64, string) {
-	amountRaw = strings.ReplaceAll(amountRaw, ",", "")
-	value, err := strconv.ParseFloat(amountRaw, 64)
-	if err != nil {
-		return 0, normalizeCurrency(currency)
-	}
-	return value, normalizeCurrency(currency)
-}
-
-func normalizeCurrency(cur string) string {
-	cur = strings.TrimSpace(cur)
-	if cur == "" {
-		return ""
-	}
-	if strings.Contains(cur, "₹") {
-		return "₹"
-	}
-	normalized := strings.ToLower(cur)
-	normalized = strings.Trim(normalized, ".")
-	switch normalized {
-	case "rs", "rs.", "inr":
-		return "₹"
-	}
-	return cur
-}
@@ -9,8 +9,8 @@ import (
 
 func TestComputeSummary(t *testing.T) {
 	orders := []zomato.Order{
-		{Total: "₹100", Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
-		{Total: "₹200", Status: "Delivered", PlacedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{Total: rupees(100), Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Total: rupees(200), Status: "Delivered", PlacedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)},
 	}
 
 	got := ComputeSummary(orders)
@@ -18,22 +18,39 @@ func TestComputeSummary(t *testing.T) {
 	if got.Count != 2 {
 		t.Errorf("Count = %d, want 2", got.Count)
 	}
-	if got.Total != 300.0 {
-		t.Errorf("Total = %f, want 300.0", got.Total)
+	if got.Total != rupees(300) {
+		t.Errorf("Total = %v, want ₹300.00", got.Total)
 	}
-	if got.Currency != "₹" {
-		t.Errorf("Currency = %q, want ₹", got.Currency)
+	if got.Average != rupees(150) {
+		t.Errorf("Average = %v, want ₹150.00", got.Average)
+	}
+	if got.Currency != "INR" {
+		t.Errorf("Currency = %q, want INR", got.Currency)
 	}
 	if got.Earliest.IsZero() || got.Latest.IsZero() {
 		t.Error("Earliest/Latest should not be zero")
 	}
 }
 
+func TestComputeSummaryMixedCurrencies(t *testing.T) {
+	orders := []zomato.Order{
+		{Total: rupees(100), PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{Total: zomato.Money{Amount: 4000, Currency: "AED"}, PlacedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	got := ComputeSummary(orders)
+	if got.Count != 2 || got.Latest.Day() != 2 {
+		t.Errorf("Summary = %+v, want 2 orders up to Jan 2", got)
+	}
+	if !got.Total.IsZero() || !got.Average.IsZero() || got.Currency != "" {
+		t.Errorf("Summary of mixed currencies has Total %v, Average %v, Currency %q; want none", got.Total, got.Average, got.Currency)
+	}
+}
+
 func TestGroupOrders(t *testing.T) {
 	orders := []zomato.Order{
-		{Total: "₹100", PlacedAt: time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)}, // Jan
-		{Total: "₹200", PlacedAt: time.Date(2023, 1, 20, 10, 0, 0, 0, time.UTC)}, // Jan
-		{Total: "₹300", PlacedAt: time.Date(2023, 2, 10, 10, 0, 0, 0, time.UTC)}, // Feb
+		{Total: rupees(100), PlacedAt: time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)}, // Jan
+		{Total: rupees(200), PlacedAt: time.Date(2023, 1, 20, 10, 0, 0, 0, time.UTC)}, // Jan
+		{Total: rupees(300), PlacedAt: time.Date(2023, 2, 10, 10, 0, 0, 0, time.UTC)}, // Feb
 	}
 
 	groups, err := GroupOrders(orders, "month")
@@ -53,8 +70,8 @@ func TestGroupOrders(t *testing.T) {
 	if jan.Count != 2 {
 		t.Errorf("Jan count = %d, want 2", jan.Count)
 	}
-	if jan.Total != 300.0 {
-		t.Errorf("Jan total = %f, want 300.0", jan.Total)
+	if jan.Total != rupees(300) {
+		t.Errorf("Jan total = %v, want ₹300.00", jan.Total)
 	}
 
 	feb := groups[1]
@@ -83,23 +100,6 @@ func TestTopRestaurants(t *testing.T) {
 	}
 }
 
-func TestParseAmount(t *testing.T) {
-	tests := []struct {
-		input    string
-		wantVal  float64
-		wantCur  string
-	}{
-		{"₹123.45", 123.45, "₹"},
-		{"Rs. 100", 100.0, "₹"},
-		{"1,234.56", 1234.56, ""},
-		{"$50", 50.0, "$"},
-		{"invalid", 0.0, ""},
-	}
-
-	for _, tt := range tests {
-		val, cur := parseAmount(tt.input)
-		if val != tt.wantVal || cur != tt.wantCur {
-			t.Errorf("parseAmount(%q) = (%v, %q), want (%v, %q)", tt.input, val, cur, tt.wantVal, tt.wantCur)
-		}
-	}
+func rupees(major int64) zomato.Money {
+	return zomato.Money{Amount: major * 100, Currency: "INR"}
 }
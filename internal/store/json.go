@@ -0,0 +1,93 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+// backupGenerations is how many previous versions of orders.json are kept.
+const backupGenerations = 5
+
+// JSONStore keeps all orders in a single JSON file.
+type JSONStore struct {
+	path string
+}
+
+func New(path string) (*JSONStore, error) {
+	if path == "" {
+		return nil, errors.New("store path is required")
+	}
+	return &JSONStore{path: path}, nil
+}
+
+func (s *JSONStore) Path() string {
+	return s.path
+}
+
+func (s *JSONStore) Load() ([]zomato.Order, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var orders []zomato.Order
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].PlacedAt.After(orders[j].PlacedAt)
+	})
+	return orders, nil
+}
+
+func (s *JSONStore) Save(orders []zomato.Order) error {
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(s.path, data, 0o600, backupGenerations)
+}
+
+func (s *JSONStore) Merge(orders []zomato.Order) (MergeResult, error) {
+	existing, err := s.Load()
+	if err != nil && !os.IsNotExist(err) {
+		return MergeResult{}, err
+	}
+	merged, result := mergeOrders(existing, orders, time.Now())
+	if result.Added == 0 && result.Updated == 0 && existing != nil {
+		return result, nil
+	}
+	if err := s.Save(merged); err != nil {
+		return MergeResult{}, err
+	}
+	return result, nil
+}
+
+func (s *JSONStore) Query(q Query) ([]zomato.Order, error) {
+	orders, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	var out []zomato.Order
+	for _, order := range orders {
+		if q.matches(order) {
+			out = append(out, order)
+		}
+	}
+	return out, nil
+}
+
+// Backups lists the rolling backups of the store file, newest first.
+func (s *JSONStore) Backups() ([]fileutil.Backup, error) {
+	return fileutil.Backups(s.path)
+}
+
+// Restore replaces the store file with a backup generation.
+func (s *JSONStore) Restore(generation int) error {
+	return fileutil.Restore(s.path, generation, 0o600, backupGenerations)
+}
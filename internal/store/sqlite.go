@@ -0,0 +1,229 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+
+	"modernc.org/sqlite"
+)
+
+// SQLite's lower() only folds ASCII; fold_lower matches the JSON store's
+// strings.ToLower so both backends agree on names like "Café".
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("fold_lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, _ := args[0].(string)
+		return strings.ToLower(s), nil
+	})
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS orders (
+	id         TEXT PRIMARY KEY,
+	restaurant TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	placed_at  INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS orders_placed_at ON orders(placed_at);
+CREATE INDEX IF NOT EXISTS orders_restaurant ON orders(restaurant);
+`
+
+// SQLiteStore keeps orders in an embedded SQLite database. The indexed
+// columns serve queries; the full order is kept as JSON in the data column
+// so new Order fields need no schema change.
+type SQLiteStore struct {
+	path string
+}
+
+func NewSQLite(path string) (*SQLiteStore, error) {
+	if path == "" {
+		return nil, errors.New("store path is required")
+	}
+	return &SQLiteStore{path: path}, nil
+}
+
+func (s *SQLiteStore) Path() string {
+	return s.path
+}
+
+func (s *SQLiteStore) Load() ([]zomato.Order, error) {
+	return s.Query(Query{})
+}
+
+func (s *SQLiteStore) Query(q Query) ([]zomato.Order, error) {
+	// Reading must not create an empty database as a side effect.
+	if _, err := os.Stat(s.path); err != nil {
+		return nil, err
+	}
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var where []string
+	var args []any
+	if !q.Since.IsZero() {
+		where = append(where, "placed_at >= ?")
+		args = append(args, q.Since.Unix())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "placed_at < ?")
+		args = append(args, q.Until.Unix())
+	}
+	if q.Restaurant != "" {
+		where = append(where, "instr(fold_lower(restaurant), ?) > 0")
+		args = append(args, strings.ToLower(q.Restaurant))
+	}
+	if q.Status != "" {
+		where = append(where, "fold_lower(trim(status)) = ?")
+		args = append(args, strings.ToLower(strings.TrimSpace(q.Status)))
+	}
+
+	stmt := "SELECT data FROM orders"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY placed_at DESC, id"
+
+	rows, err := db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []zomato.Order{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var order zomato.Order
+		if err := json.Unmarshal([]byte(data), &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (s *SQLiteStore) Save(orders []zomato.Order) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM orders"); err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if err := upsertOrder(tx, order); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Merge(orders []zomato.Order) (MergeResult, error) {
+	var result MergeResult
+	db, err := s.open()
+	if err != nil {
+		return result, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, order := range orders {
+		old, err := selectOrder(tx, order.ID)
+		if err != nil {
+			return MergeResult{}, err
+		}
+		stored, outcome := mergeOne(old, order, now)
+		result.count(outcome)
+		if outcome == outcomeUnchanged {
+			continue
+		}
+		if err := upsertOrder(tx, stored); err != nil {
+			return MergeResult{}, err
+		}
+	}
+	if err := tx.QueryRow("SELECT COUNT(*) FROM orders").Scan(&result.Total); err != nil {
+		return MergeResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return MergeResult{}, err
+	}
+	return result, nil
+}
+
+func (s *SQLiteStore) open() (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", s.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func selectOrder(tx *sql.Tx, id string) (*zomato.Order, error) {
+	var data string
+	err := tx.QueryRow("SELECT data FROM orders WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var order zomato.Order
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func upsertOrder(tx *sql.Tx, order zomato.Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO orders (id, restaurant, status, placed_at, data)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			restaurant = excluded.restaurant,
+			status = excluded.status,
+			placed_at = excluded.placed_at,
+			data = excluded.data`,
+		order.ID, order.Restaurant, order.Status, order.PlacedAt.Unix(), string(data))
+	return err
+}
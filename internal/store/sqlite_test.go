@@ -0,0 +1,116 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestSQLiteStore_LoadMissing(t *testing.T) {
+	s, err := NewSQLite(filepath.Join(t.TempDir(), "orders.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(); !os.IsNotExist(err) {
+		t.Errorf("Load missing db err = %v, want os.IsNotExist", err)
+	}
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Error("Load must not create the database file")
+	}
+}
+
+func TestSQLiteStore_MergeQuery(t *testing.T) {
+	s, err := NewSQLite(filepath.Join(t.TempDir(), "orders.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders := []zomato.Order{
+		{ID: "1", Restaurant: "Pizza Hut", Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "2", Restaurant: "Dominos", Status: "Delivered", PlacedAt: time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "3", Restaurant: "Pizza Express", Status: "Cancelled", PlacedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+			Items: []zomato.OrderItem{{Name: "Margherita", Quantity: 2}}},
+	}
+	result, err := s.Merge(orders)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.Added != 3 || result.Total != 3 {
+		t.Errorf("Merge = %+v, want 3 added", result)
+	}
+
+	orders[1].Status = "Refunded"
+	result, err = s.Merge(orders[1:2])
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := MergeResult{Updated: 1, Total: 3}
+	if result != want {
+		t.Errorf("Merge = %+v, want %+v", result, want)
+	}
+
+	loaded, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 3 || loaded[0].ID != "3" || loaded[2].ID != "1" {
+		t.Fatalf("Load returned %v, want newest first", loaded)
+	}
+	if len(loaded[0].Items) != 1 || loaded[0].Items[0].Quantity != 2 {
+		t.Errorf("Items not round-tripped: %v", loaded[0].Items)
+	}
+
+	got, err := s.Query(Query{Restaurant: "pizza", Since: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "3" {
+		t.Errorf("Query restaurant+since = %v, want order 3", got)
+	}
+
+	got, err = s.Query(Query{Status: "refunded"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("Query status = %v, want order 2", got)
+	}
+}
+
+func TestQueryFoldsNonASCII(t *testing.T) {
+	dir := t.TempDir()
+	jsonStore, err := New(filepath.Join(dir, "orders.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqliteStore, err := NewSQLite(filepath.Join(dir, "orders.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := []zomato.Order{
+		{ID: "1", Restaurant: "CAFÉ MOCHA", Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "2", Restaurant: "Cafe Coffee Day", Status: " Delivered ", PlacedAt: time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for name, s := range map[string]Store{"json": jsonStore, "sqlite": sqliteStore} {
+		if err := s.Save(orders); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Query(Query{Restaurant: "café"})
+		if err != nil {
+			t.Fatalf("%s: Query failed: %v", name, err)
+		}
+		if len(got) != 1 || got[0].ID != "1" {
+			t.Errorf("%s: Query restaurant = %v, want order 1", name, got)
+		}
+		got, err = s.Query(Query{Status: "delivered"})
+		if err != nil {
+			t.Fatalf("%s: Query failed: %v", name, err)
+		}
+		if len(got) != 2 {
+			t.Errorf("%s: Query status = %v, want both orders", name, got)
+		}
+	}
+}
@@ -1,60 +1,206 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
+	"strings"
+	"time"
 
 	"github.com/maheshrijal/zocli/internal/zomato"
 )
 
-type Store struct {
-	path string
+// Supported storage backends.
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// Store persists orders locally.
+type Store interface {
+	// Load returns every stored order, newest first. It returns an error
+	// satisfying os.IsNotExist when nothing has been stored yet.
+	Load() ([]zomato.Order, error)
+	// Save replaces the stored orders.
+	Save(orders []zomato.Order) error
+	// Merge upserts orders by ID, keeping stored orders missing from the
+	// given slice.
+	Merge(orders []zomato.Order) (MergeResult, error)
+	// Query returns the stored orders matching q, newest first.
+	Query(q Query) ([]zomato.Order, error)
+	// Path is the file backing the store.
+	Path() string
+}
+
+// Query selects a subset of stored orders. Zero fields match everything.
+type Query struct {
+	Since      time.Time // inclusive
+	Until      time.Time // exclusive
+	Restaurant string    // case-insensitive substring
+	Status     string    // case-insensitive exact match, ignoring spaces
+}
+
+// MergeResult reports how Merge treated each incoming order.
+type MergeResult struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Total     int
 }
 
 func DefaultPath() (string, error) {
+	return DefaultPathFor(BackendJSON)
+}
+
+// DefaultPathFor returns the default file location for a backend.
+func DefaultPathFor(backend string) (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
 	}
-	newPath := filepath.Join(dir, "zocli", "orders.json")
-	oldPath := filepath.Join(dir, "zomatocli", "orders.json")
-	return preferPath(newPath, oldPath, 0o600), nil
+	if normalizeBackend(backend) == BackendJSON {
+		newPath := filepath.Join(dir, "zocli", "orders.json")
+		oldPath := filepath.Join(dir, "zomatocli", "orders.json")
+		return preferPath(newPath, oldPath, 0o600), nil
+	}
+	return PathFor(filepath.Join(dir, "zocli"), backend)
 }
 
-func New(path string) (*Store, error) {
-	if path == "" {
-		return nil, errors.New("store path is required")
+// PathFor returns the store file for a backend inside an account's data dir.
+func PathFor(dir, backend string) (string, error) {
+	switch normalizeBackend(backend) {
+	case BackendJSON:
+		return filepath.Join(dir, "orders.json"), nil
+	case BackendSQLite:
+		return filepath.Join(dir, "orders.db"), nil
+	default:
+		return "", fmt.Errorf("unknown store backend: %s (use json or sqlite)", backend)
 	}
-	return &Store{path: path}, nil
 }
 
-func (s *Store) Load() ([]zomato.Order, error) {
-	data, err := os.ReadFile(s.path)
-	if err != nil {
-		return nil, err
+// Open returns the store for backend at path.
+func Open(backend, path string) (Store, error) {
+	switch normalizeBackend(backend) {
+	case BackendJSON:
+		return New(path)
+	case BackendSQLite:
+		return NewSQLite(path)
+	default:
+		return nil, fmt.Errorf("unknown store backend: %s (use json or sqlite)", backend)
 	}
-	var orders []zomato.Order
-	if err := json.Unmarshal(data, &orders); err != nil {
-		return nil, err
+}
+
+func normalizeBackend(backend string) string {
+	backend = strings.ToLower(strings.TrimSpace(backend))
+	if backend == "" {
+		return BackendJSON
 	}
-	sort.Slice(orders, func(i, j int) bool {
-		return orders[i].PlacedAt.After(orders[j].PlacedAt)
-	})
-	return orders, nil
+	return backend
 }
 
-func (s *Store) Save(orders []zomato.Order) error {
-	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
-		return err
+func (q Query) matches(order zomato.Order) bool {
+	if !q.Since.IsZero() && order.PlacedAt.Before(q.Since) {
+		return false
 	}
-	data, err := json.MarshalIndent(orders, "", "  ")
-	if err != nil {
-		return err
+	if !q.Until.IsZero() && !order.PlacedAt.Before(q.Until) {
+		return false
+	}
+	if q.Restaurant != "" && !strings.Contains(strings.ToLower(order.Restaurant), strings.ToLower(q.Restaurant)) {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(strings.TrimSpace(order.Status), strings.TrimSpace(q.Status)) {
+		return false
+	}
+	return true
+}
+
+type mergeOutcome int
+
+const (
+	outcomeAdded mergeOutcome = iota
+	outcomeUpdated
+	outcomeUnchanged
+)
+
+// mergeOne decides what to store for an incoming order given the stored one
+// (nil when the order is new).
+func mergeOne(old *zomato.Order, order zomato.Order, now time.Time) (zomato.Order, mergeOutcome) {
+	if old == nil {
+		order.FirstSeen = now
+		order.LastUpdated = now
+		return order, outcomeAdded
+	}
+	// The order list never carries details, so a plain re-sync must not
+	// drop the priced items fetched earlier from the detail endpoint.
+	if order.Details == nil && old.Details != nil {
+		order.Details = old.Details
+		order.Items = old.Items
+	}
+	if order.HashID == "" {
+		order.HashID = old.HashID
+	}
+	if sameOrder(*old, order) {
+		return *old, outcomeUnchanged
+	}
+	order.FirstSeen = old.FirstSeen
+	if order.FirstSeen.IsZero() {
+		order.FirstSeen = now
+	}
+	order.LastUpdated = now
+	return order, outcomeUpdated
+}
+
+func (r *MergeResult) count(outcome mergeOutcome) {
+	switch outcome {
+	case outcomeAdded:
+		r.Added++
+	case outcomeUpdated:
+		r.Updated++
+	default:
+		r.Unchanged++
+	}
+}
+
+func mergeOrders(existing, incoming []zomato.Order, now time.Time) ([]zomato.Order, MergeResult) {
+	var result MergeResult
+	index := make(map[string]int, len(existing))
+	merged := make([]zomato.Order, 0, len(existing)+len(incoming))
+	for _, order := range existing {
+		index[order.ID] = len(merged)
+		merged = append(merged, order)
+	}
+
+	for _, order := range incoming {
+		i, ok := index[order.ID]
+		if !ok {
+			stored, outcome := mergeOne(nil, order, now)
+			index[order.ID] = len(merged)
+			merged = append(merged, stored)
+			result.count(outcome)
+			continue
+		}
+		stored, outcome := mergeOne(&merged[i], order, now)
+		merged[i] = stored
+		result.count(outcome)
+	}
+
+	result.Total = len(merged)
+	return merged, result
+}
+
+// sameOrder compares orders by their serialized form, ignoring the
+// bookkeeping timestamps owned by the store.
+func sameOrder(a, b zomato.Order) bool {
+	a.FirstSeen, a.LastUpdated = time.Time{}, time.Time{}
+	b.FirstSeen, b.LastUpdated = time.Time{}, time.Time{}
+	aj, aerr := json.Marshal(a)
+	bj, berr := json.Marshal(b)
+	if aerr != nil || berr != nil {
+		return false
 	}
-	return os.WriteFile(s.path, data, 0o600)
+	return bytes.Equal(aj, bj)
 }
 
 func preferPath(newPath, oldPath string, perm os.FileMode) string {
@@ -80,3 +80,138 @@ func TestDefaultPath(t *testing.T) {
 		t.Error("DefaultPath returned empty string")
 	}
 }
+
+func TestStore_Merge(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "zocli_store_merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := New(filepath.Join(tmpDir, "orders.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := []zomato.Order{
+		{ID: "1", Restaurant: "A", Status: "Preparing", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "2", Restaurant: "B", Status: "Delivered", PlacedAt: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	result, err := s.Merge(first)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.Added != 2 || result.Total != 2 {
+		t.Errorf("First merge = %+v, want 2 added, 2 total", result)
+	}
+
+	// Order 1 changed, order 2 missing, order 3 new.
+	second := []zomato.Order{
+		{ID: "1", Restaurant: "A", Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "3", Restaurant: "C", Status: "Delivered", PlacedAt: time.Date(2023, 1, 3, 10, 0, 0, 0, time.UTC)},
+	}
+	result, err = s.Merge(second)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := MergeResult{Added: 1, Updated: 1, Unchanged: 0, Total: 3}
+	if result != want {
+		t.Errorf("Second merge = %+v, want %+v", result, want)
+	}
+
+	result, err = s.Merge(second)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.Unchanged != 2 || result.Added != 0 || result.Updated != 0 {
+		t.Errorf("Repeated merge = %+v, want 2 unchanged", result)
+	}
+
+	loaded, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("Loaded %d orders, want 3 (missing orders must be kept)", len(loaded))
+	}
+	for _, order := range loaded {
+		if order.FirstSeen.IsZero() || order.LastUpdated.IsZero() {
+			t.Errorf("Order %s missing first_seen/last_updated", order.ID)
+		}
+		if order.ID == "1" && order.Status != "Delivered" {
+			t.Errorf("Order 1 status = %q, want Delivered", order.Status)
+		}
+	}
+}
+
+func TestStore_MergeKeepsDetails(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "orders.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placed := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	price := zomato.Money{Amount: 15000, Currency: "INR"}
+	enriched := zomato.Order{
+		ID:       "1",
+		HashID:   "h1",
+		PlacedAt: placed,
+		Items:    []zomato.OrderItem{{Name: "Burger", Quantity: 1, UnitPrice: price, TotalPrice: price}},
+		Details:  &zomato.OrderDetails{ItemTotal: price, DeliveryFee: zomato.Money{Amount: 3000, Currency: "INR"}},
+	}
+	if _, err := s.Merge([]zomato.Order{enriched}); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	// A regular sync only knows the item names from the order list.
+	plain := zomato.Order{
+		ID:       "1",
+		HashID:   "h1",
+		PlacedAt: placed,
+		Items:    []zomato.OrderItem{{Name: "Burger", Quantity: 1}},
+	}
+	result, err := s.Merge([]zomato.Order{plain})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.Unchanged != 1 {
+		t.Errorf("Merge = %+v, want 1 unchanged", result)
+	}
+
+	loaded, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded[0].Details == nil || loaded[0].Details.DeliveryFee.Amount != 3000 {
+		t.Errorf("Details = %+v, want cached details kept", loaded[0].Details)
+	}
+	if loaded[0].Items[0].UnitPrice != price {
+		t.Errorf("UnitPrice = %v, want 150", loaded[0].Items[0].UnitPrice)
+	}
+}
+
+func TestStore_Query(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "orders.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := []zomato.Order{
+		{ID: "1", Restaurant: "Pizza Hut", Status: "Delivered", PlacedAt: time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "2", Restaurant: "Dominos", Status: "Delivered", PlacedAt: time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: "3", Restaurant: "Pizza Express", Status: "Cancelled", PlacedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	if err := s.Save(orders); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query(Query{
+		Restaurant: "PIZZA",
+		Until:      time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("Query = %v, want only order 1 (until is exclusive)", got)
+	}
+}
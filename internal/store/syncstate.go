@@ -0,0 +1,60 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/maheshrijal/zocli/internal/fileutil"
+)
+
+// SyncState records where an interrupted sync stopped so the next run can
+// resume from that page instead of starting over.
+type SyncState struct {
+	NextPage  int       `json:"next_page"`
+	Full      bool      `json:"full,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyncStatePath returns the resume state file kept next to the store file.
+func SyncStatePath(storePath string) string {
+	return filepath.Join(filepath.Dir(storePath), "sync-state.json")
+}
+
+// LoadSyncState reads the resume state. A missing file yields a zero state.
+func LoadSyncState(path string) (SyncState, error) {
+	var state SyncState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func SaveSyncState(path string, state SyncState) error {
+	if state.UpdatedAt.IsZero() {
+		state.UpdatedAt = time.Now()
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(path, data, 0o600, 0)
+}
+
+// ClearSyncState removes the resume state after a sync completes.
+func ClearSyncState(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
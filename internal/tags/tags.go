@@ -0,0 +1,212 @@
+// Package tags assigns cuisine and category tags such as "dessert" or
+// "biryani" to orders, from restaurant and dish keywords plus manual
+// per-order edits.
+package tags
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maheshrijal/zocli/internal/names"
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+//go:embed default_rules.json
+var defaultRules []byte
+
+// Rule tags an order when its restaurant name or any of its dish names
+// contains one of the keywords. Keywords match at the start of a word,
+// ignoring case and punctuation, so "cake" matches "Cakes" but not
+// "Pancake".
+type Rule struct {
+	Restaurants []string `json:"restaurants,omitempty"`
+	Items       []string `json:"items,omitempty"`
+}
+
+// Rules maps each tag to its rule.
+type Rules map[string]Rule
+
+// Manual is a manual edit of one order's tags, applied after the rules.
+type Manual struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// DefaultRules returns the built-in keyword rules.
+func DefaultRules() Rules {
+	var rules Rules
+	if err := json.Unmarshal(defaultRules, &rules); err != nil {
+		panic(fmt.Sprintf("tags: invalid default rules: %v", err))
+	}
+	return rules
+}
+
+// LoadRules reads a user rules file. A missing file means no user rules.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Rules{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	normalized := make(Rules, len(rules))
+	for tag, rule := range rules {
+		name, err := ParseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		normalized[name] = rule
+	}
+	return normalized, nil
+}
+
+// Merge returns the default rules extended by user rules: keywords for a
+// tag that exists in both are combined.
+func Merge(defaults, user Rules) Rules {
+	out := make(Rules, len(defaults)+len(user))
+	for tag, rule := range defaults {
+		out[tag] = rule
+	}
+	for tag, rule := range user {
+		base := out[tag]
+		base.Restaurants = append(append([]string(nil), base.Restaurants...), rule.Restaurants...)
+		base.Items = append(append([]string(nil), base.Items...), rule.Items...)
+		out[tag] = base
+	}
+	return out
+}
+
+// ParseTag validates a tag name: lower case letters, digits and dashes.
+func ParseTag(value string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(value))
+	if tag == "" {
+		return "", errors.New("empty tag")
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return "", fmt.Errorf("invalid tag %q (use letters, digits and dashes)", value)
+		}
+	}
+	return tag, nil
+}
+
+// Tagger tags orders.
+type Tagger struct {
+	restaurants map[string][]string // tag -> folded keywords
+	items       map[string][]string
+	manual      map[string]Manual // by order ID
+}
+
+// NewTagger returns a Tagger applying rules, then the manual edits keyed
+// by order ID.
+func NewTagger(rules Rules, manual map[string]Manual) *Tagger {
+	t := &Tagger{
+		restaurants: map[string][]string{},
+		items:       map[string][]string{},
+		manual:      manual,
+	}
+	for tag, rule := range rules {
+		t.restaurants[tag] = foldAll(rule.Restaurants)
+		t.items[tag] = foldAll(rule.Items)
+	}
+	return t
+}
+
+func foldAll(keywords []string) []string {
+	out := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		if f := names.Fold(k); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Tags returns the order's tags, sorted.
+func (t *Tagger) Tags(order zomato.Order) []string {
+	set := map[string]bool{}
+	restaurant := " " + names.Fold(order.Restaurant)
+	for tag, keywords := range t.restaurants {
+		if containsWord(restaurant, keywords) {
+			set[tag] = true
+		}
+	}
+	for _, item := range order.Items {
+		name := " " + names.Fold(item.Name)
+		for tag, keywords := range t.items {
+			if !set[tag] && containsWord(name, keywords) {
+				set[tag] = true
+			}
+		}
+	}
+	if m, ok := t.manual[order.ID]; ok {
+		for _, tag := range m.Add {
+			set[tag] = true
+		}
+		for _, tag := range m.Remove {
+			delete(set, tag)
+		}
+	}
+	out := make([]string, 0, len(set))
+	for tag := range set {
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// containsWord reports whether text, a folded name with a leading space,
+// has a word starting with one of the keywords.
+func containsWord(text string, keywords []string) bool {
+	for _, k := range keywords {
+		if strings.Contains(text, " "+k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Edit applies "+tag" and "-tag" changes to an order's manual edits. A
+// tag added and then removed (or the reverse) ends up only in the later
+// list.
+func Edit(m Manual, changes []string) (Manual, error) {
+	for _, change := range changes {
+		if len(change) < 2 || (change[0] != '+' && change[0] != '-') {
+			return m, fmt.Errorf("invalid change %q (use +tag or -tag)", change)
+		}
+		tag, err := ParseTag(change[1:])
+		if err != nil {
+			return m, err
+		}
+		m.Add = without(m.Add, tag)
+		m.Remove = without(m.Remove, tag)
+		if change[0] == '+' {
+			m.Add = append(m.Add, tag)
+		} else {
+			m.Remove = append(m.Remove, tag)
+		}
+	}
+	sort.Strings(m.Add)
+	sort.Strings(m.Remove)
+	return m, nil
+}
+
+func without(list []string, tag string) []string {
+	out := list[:0:0]
+	for _, t := range list {
+		if t != tag {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/maheshrijal/zocli/internal/zomato"
+)
+
+func TestTaggerTags(t *testing.T) {
+	rules := Rules{
+		"pizza":   {Restaurants: []string{"pizza", "domino's"}, Items: []string{"margherita"}},
+		"dessert": {Items: []string{"cake", "brownie"}},
+	}
+	manual := map[string]Manual{
+		"3": {Add: []string{"party"}, Remove: []string{"pizza"}},
+	}
+	tagger := NewTagger(rules, manual)
+
+	tests := []struct {
+		name  string
+		order zomato.Order
+		want  []string
+	}{
+		{"restaurant keyword", zomato.Order{Restaurant: "Dominos Pizza - HSR"}, []string{"pizza"}},
+		{"item keyword", zomato.Order{Restaurant: "Baker Street", Items: []zomato.OrderItem{{Name: "Choco Lava Cakes"}}}, []string{"dessert"}},
+		{"word start only", zomato.Order{Restaurant: "Pancake House", Items: []zomato.OrderItem{{Name: "Pancakes"}}}, []string{}},
+		{"several tags", zomato.Order{Restaurant: "La Pino'z", Items: []zomato.OrderItem{{Name: "Margherita"}, {Name: "Brownie"}}}, []string{"dessert", "pizza"}},
+		{"manual edits", zomato.Order{ID: "3", Restaurant: "Pizza Hut"}, []string{"party"}},
+	}
+	for _, tt := range tests {
+		if got := tagger.Tags(tt.order); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Tags = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEdit(t *testing.T) {
+	m, err := Edit(Manual{Remove: []string{"dessert"}}, []string{"+Dessert", "-pizza", "+pizza"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Manual{Add: []string{"dessert", "pizza"}, Remove: []string{}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Edit = %+v, want %+v", m, want)
+	}
+
+	for _, change := range []string{"dessert", "+", "+ice cream"} {
+		if _, err := Edit(Manual{}, []string{change}); err == nil {
+			t.Errorf("Edit(%q) succeeded, want error", change)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	defaults := Rules{"dessert": {Items: []string{"cake"}}}
+	user := Rules{"dessert": {Items: []string{"kulfi"}}, "momos": {Items: []string{"momo"}}}
+	got := Merge(defaults, user)
+	if items := got["dessert"].Items; !reflect.DeepEqual(items, []string{"cake", "kulfi"}) {
+		t.Errorf("dessert items = %v, want [cake kulfi]", items)
+	}
+	if _, ok := got["momos"]; !ok {
+		t.Error("user tag momos missing after Merge")
+	}
+	if len(defaults["dessert"].Items) != 1 {
+		t.Errorf("Merge modified the defaults: %v", defaults["dessert"].Items)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	rules, err := LoadRules(filepath.Join(dir, "missing.json"))
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("LoadRules(missing) = %v, %v; want empty", rules, err)
+	}
+
+	path := filepath.Join(dir, "tag_rules.json")
+	if err := os.WriteFile(path, []byte(`{"Momos": {"items": ["momo"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadRules(path)
+	if err != nil || len(rules["momos"].Items) != 1 {
+		t.Errorf("LoadRules = %v, %v; want momos rule", rules, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"ice cream": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Error("LoadRules with an invalid tag name succeeded, want error")
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	rules := DefaultRules()
+	for tag := range rules {
+		if _, err := ParseTag(tag); err != nil {
+			t.Errorf("default tag %q: %v", tag, err)
+		}
+	}
+	got := NewTagger(rules, nil).Tags(zomato.Order{Restaurant: "Behrouz Biryani"})
+	if !reflect.DeepEqual(got, []string{"biryani"}) {
+		t.Errorf("Tags(Behrouz Biryani) = %v, want [biryani]", got)
+	}
+}
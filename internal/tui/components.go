@@ -27,7 +27,7 @@ func (m *Model) initOrderTable() {
 			order.PlacedAt.Format("2006-01-02"),
 			order.Restaurant,
 			items,
-			order.Total,
+			order.Total.String(),
 			order.Status,
 		}
 	}
@@ -60,7 +60,11 @@ func (m *Model) updateTableSize() {
 	
 	if m.inflationTable.Focused() { // Or just always resize both
 		m.inflationTable.SetWidth(m.width - 2)
-		m.inflationTable.SetHeight(m.height - 10)
+		height := m.height - 10
+		if len(m.index) >= 2 {
+			height -= indexChartHeight + 2 // chart title and legend
+		}
+		m.inflationTable.SetHeight(max(height, 3))
 	}
 }
 
@@ -2,18 +2,23 @@ package tui
 
 import (
 	"fmt" // Import fmt
+	"math"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 	
 	"github.com/maheshrijal/zocli/internal/stats"
+	"github.com/maheshrijal/zocli/internal/zomato"
 )
 
 func (m *Model) initInflationTable() {
 	columns := []table.Column{
-		{Title: "Item", Width: 35},
+		{Title: "Item", Width: 27},
 		{Title: "Restaurant", Width: 25},
 		{Title: "Old", Width: 10},
 		{Title: "New", Width: 10},
+		{Title: "Conf", Width: 6},
 		{Title: "Change", Width: 12},
 	}
 
@@ -32,8 +37,9 @@ func (m *Model) initInflationTable() {
 		rows[i] = table.Row{
 			t.ItemName,
 			t.Restaurant,
-			fmt.Sprintf("%.0f", t.FirstPrice), // Use fmt.Sprintf
-			fmt.Sprintf("%.0f", t.LastPrice),  // Use fmt.Sprintf
+			fmt.Sprintf("%s%.0f", zomato.CurrencySymbol(t.Currency), t.FirstPrice),
+			fmt.Sprintf("%s%.0f", zomato.CurrencySymbol(t.Currency), t.LastPrice),
+			fmt.Sprintf("%.0f%%", t.Confidence*100),
 			changeStr,
 		}
 	}
@@ -61,5 +67,76 @@ func (m *Model) initInflationTable() {
 }
 
 func (m Model) viewInflation() string {
-	return m.styles.TableContainer.Render(m.inflationTable.View())
+	table := m.styles.TableContainer.Render(m.inflationTable.View())
+	chart := m.viewIndexChart()
+	if chart == "" {
+		return table
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, chart, table)
+}
+
+// indexChartHeight is the number of rows the index chart's bars span.
+const indexChartHeight = 8
+
+// viewIndexChart draws the personal inflation index as bars, one per month,
+// with the reference CPI as dots when known.
+func (m Model) viewIndexChart() string {
+	points := m.index
+	if len(points) < 2 {
+		return ""
+	}
+	const labelWidth = 7
+	if cols := m.width - labelWidth - 4; cols > 0 && len(points) > cols {
+		points = points[len(points)-cols:]
+	}
+	lo, hi := points[0].Index, points[0].Index
+	hasCPI := false
+	for _, p := range points {
+		lo, hi = math.Min(lo, p.Index), math.Max(hi, p.Index)
+		if p.CPI > 0 {
+			lo, hi = math.Min(lo, p.CPI), math.Max(hi, p.CPI)
+			hasCPI = true
+		}
+	}
+	if hi-lo < 1 {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	level := func(v float64) int {
+		return 1 + int(math.Round((v-lo)/(hi-lo)*float64(indexChartHeight-1)))
+	}
+
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	dot := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	axis := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	var lines []string
+	for row := indexChartHeight; row >= 1; row-- {
+		label := ""
+		switch row {
+		case indexChartHeight:
+			label = fmt.Sprintf("%.1f", hi)
+		case 1:
+			label = fmt.Sprintf("%.1f", lo)
+		}
+		var b strings.Builder
+		b.WriteString(axis.Render(fmt.Sprintf("%*s │", labelWidth-2, label)))
+		for _, p := range points {
+			switch {
+			case p.CPI > 0 && level(p.CPI) == row:
+				b.WriteString(dot.Render("●"))
+			case level(p.Index) >= row:
+				b.WriteString(bar.Render("█"))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	last := points[len(points)-1]
+	legend := fmt.Sprintf("%*s %s to %s · index %.1f", labelWidth-2, "", points[0].Label, last.Label, last.Index)
+	if hasCPI {
+		legend += fmt.Sprintf(" · %s CPI %.1f", dot.Render("●"), last.CPI)
+	}
+	lines = append(lines, axis.Render(legend))
+	title := m.styles.Title.Render(fmt.Sprintf("Personal Food Inflation (%s, monthly)", zomato.CurrencySymbol(last.Currency)))
+	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.JoinVertical(lipgloss.Left, lines...))
 }
@@ -2,12 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	
+	"github.com/maheshrijal/zocli/internal/budget"
 	"github.com/maheshrijal/zocli/internal/stats"
 	"github.com/maheshrijal/zocli/internal/zomato"
 )
@@ -40,6 +42,17 @@ type Model struct {
 	allOrders []zomato.Order // Source of truth
 	orders    []zomato.Order // Filtered view
 	summary   stats.Summary
+	// totals holds one summary per currency, since amounts in different
+	// currencies can't be added.
+	totals []stats.Summary
+	// loc is the zone the year and month filters are computed in.
+	loc *time.Location
+	// budgets are shown on the summary tab against this month's orders,
+	// whatever the active filter.
+	budgets []budget.Budget
+	// index is the monthly personal inflation index of the main currency
+	// over all orders, charted on the inflation tab.
+	index []stats.IndexPoint
 
 	// Components
 	orderTable     table.Model
@@ -49,7 +62,7 @@ type Model struct {
 	styles Styles
 }
 
-func NewModel(orders []zomato.Order) Model {
+func NewModel(orders []zomato.Order, loc *time.Location, budgets []budget.Budget, cpi stats.CPI) Model {
 	summary := stats.ComputeSummary(orders)
 	
 	m := Model{
@@ -58,9 +71,19 @@ func NewModel(orders []zomato.Order) Model {
 		allOrders:    orders,
 		orders:       orders,
 		summary:      summary,
+		totals:       stats.ComputeSummaries(orders),
+		loc:          loc,
+		budgets:      budgets,
 		styles:       DefaultStyles(),
 	}
 	
+	if groups := stats.SplitByCurrency(orders); len(groups) > 0 {
+		m.index, _ = stats.PersonalInflationIndex(groups[0].Orders, "month")
+		if cpi != nil {
+			stats.CompareCPI(m.index, cpi, "month")
+		}
+	}
+	
 	m.initOrderTable()
 	m.initInflationTable()
 	return m
@@ -113,15 +136,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *Model) setFilter(f Filter) {
 	m.activeFilter = f
-	now := time.Now()
+	now := time.Now().In(m.loc)
 	
 	switch f {
 	case FilterYear:
-		start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.Local)
+		start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, m.loc)
 		end := start.AddDate(1, 0, 0) // Start of next year
 		m.orders = stats.FilterOrdersByDate(m.allOrders, start, end)
 	case FilterMonth:
-		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, m.loc)
 		end := start.AddDate(0, 1, 0) // Start of next month
 		m.orders = stats.FilterOrdersByDate(m.allOrders, start, end)
 	default:
@@ -129,6 +152,7 @@ func (m *Model) setFilter(f Filter) {
 	}
 	
 	m.summary = stats.ComputeSummary(m.orders)
+	m.totals = stats.ComputeSummaries(m.orders)
 	// Re-init tables with new data
 	m.initOrderTable()
 	m.initInflationTable()
@@ -169,10 +193,27 @@ func (m Model) renderFooter() string {
 	}
 
 	help := fmt.Sprintf("%s • %d orders • %s total • y: year • m: month • a: all • q: quit", 
-		filterStatus, m.summary.Count, itemsString(m.summary.Total))
+		filterStatus, m.summary.Count, totalsString(m.totals))
 	return m.styles.Footer.Render(help)
 }
 
-func itemsString(val float64) string {
-	return fmt.Sprintf("₹%.2f", val)
+// totalsString joins the per-currency totals, e.g. "₹1200.00 + AED 40.00".
+func totalsString(summaries []stats.Summary) string {
+	return joinMoney(summaries, " + ", func(s stats.Summary) zomato.Money { return s.Total })
+}
+
+// averagesString lists the average order value of each currency.
+func averagesString(summaries []stats.Summary) string {
+	return joinMoney(summaries, " / ", func(s stats.Summary) zomato.Money { return s.Average })
+}
+
+func joinMoney(summaries []stats.Summary, sep string, pick func(stats.Summary) zomato.Money) string {
+	if len(summaries) == 0 {
+		return zomato.Money{Currency: zomato.DefaultCurrency}.String()
+	}
+	parts := make([]string, 0, len(summaries))
+	for _, s := range summaries {
+		parts = append(parts, pick(s).String())
+	}
+	return strings.Join(parts, sep)
 }
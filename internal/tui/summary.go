@@ -2,8 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/maheshrijal/zocli/internal/budget"
 )
 
 func (m Model) viewSummary() string {
@@ -11,7 +15,7 @@ func (m Model) viewSummary() string {
 	totalBox := m.styles.StatsBox.Render(
 		lipgloss.JoinVertical(lipgloss.Center,
 			m.styles.Title.Copy().Background(lipgloss.Color("62")).Render("Total Spent"),
-			fmt.Sprintf("\n%s", itemsString(m.summary.Total)),
+			fmt.Sprintf("\n%s", totalsString(m.totals)),
 		),
 	)
 
@@ -25,7 +29,7 @@ func (m Model) viewSummary() string {
 	avgBox := m.styles.StatsBox.Render(
 		lipgloss.JoinVertical(lipgloss.Center,
 			m.styles.Title.Copy().Background(lipgloss.Color("33")).Render("Average Order"),
-			fmt.Sprintf("\n%s", itemsString(m.summary.Average)),
+			fmt.Sprintf("\n%s", averagesString(m.totals)),
 		),
 	)
 
@@ -37,11 +41,37 @@ func (m Model) viewSummary() string {
 		m.summary.Latest.Format("Jan 02, 2006"),
 	)
 	
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		row1,
-		"\n",
-		m.styles.Footer.Render(dateRange),
-	)
+	parts := []string{row1}
+	if budgets := m.viewBudgets(); budgets != "" {
+		parts = append(parts, budgets)
+	}
+	parts = append(parts, "\n", m.styles.Footer.Render(dateRange))
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
 
 	return lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, content)
 }
+
+// viewBudgets renders a progress bar per budget for the current month.
+func (m Model) viewBudgets() string {
+	statuses, err := budget.EvaluateAll(m.budgets, m.allOrders, time.Now().In(m.loc))
+	if err != nil || len(statuses) == 0 {
+		return ""
+	}
+	const width = 30
+	lines := []string{m.styles.Title.Copy().Background(lipgloss.Color("99")).Render("Budgets This Month")}
+	for _, s := range statuses {
+		color := lipgloss.Color("42")
+		switch {
+		case s.Over():
+			color = lipgloss.Color("196")
+		case !s.OnTrack():
+			color = lipgloss.Color("214")
+		}
+		filled := min(int(s.Percent()/100*width+0.5), width)
+		bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("░", width-filled))
+		lines = append(lines, fmt.Sprintf("%-18s %s %s / %s (%.0f%%) · projected %s",
+			s.Name(), bar, s.Spent, s.Monthly, s.Percent(), s.Projected))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
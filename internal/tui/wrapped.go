@@ -18,33 +18,38 @@ type WrappedModel struct {
 	
 	// Stats
 	year           int
-	totalSpent     float64
+	totalSpent     []stats.Summary
 	orderCount     int
 	topRestaurant  string
 	topItem        string
 	mostExpensive  zomato.Order
-	maxAmount      float64
+	maxAmount      zomato.Money
 	busiestWeekday string
 	busiestTime    string
-	currency       string
 }
 
 func NewWrappedModel(orders []zomato.Order, year int) WrappedModel {
 	summary := stats.ComputeSummary(orders)
 	topRes := stats.TopRestaurants(orders, 1)
 	topItems := stats.TopItems(orders, 1)
-	maxOrder, maxAmt := stats.FindMostExpensiveOrder(orders)
+	totals := stats.ComputeSummaries(orders)
+	// Amounts are only comparable within a currency, so the most expensive
+	// order comes from the currency used most.
+	var maxOrder zomato.Order
+	var maxAmt zomato.Money
+	if groups := stats.SplitByCurrency(orders); len(groups) > 0 {
+		maxOrder, maxAmt = stats.FindMostExpensiveOrder(groups[0].Orders)
+	}
 	weekdays := stats.OrdersByWeekday(orders)
 	times := stats.OrdersByTimeWindow(orders)
 	
 	m := WrappedModel{
 		orders:        orders,
 		year:          year,
-		totalSpent:    summary.Total,
+		totalSpent:    totals,
 		orderCount:    summary.Count,
 		mostExpensive: maxOrder,
 		maxAmount:     maxAmt,
-		currency:      summary.Currency,
 	}
 	
 	if len(topRes) > 0 {
@@ -158,7 +163,7 @@ func (m WrappedModel) slideTotals() string {
 	bigSpend := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("42")).
-		Render(fmt.Sprintf("%s%.2f", m.currency, m.totalSpent))
+		Render(totalsString(m.totalSpent))
 		
 	spendText := "Total value of food consumed"
 
@@ -212,7 +217,7 @@ func (m WrappedModel) slideExpensive() string {
 		Background(lipgloss.Color("#FFD700")).
 		Foreground(lipgloss.Color("#000000")).
 		Padding(0, 1).
-		Render(fmt.Sprintf("%s%.2f", m.currency, m.maxAmount))
+		Render(m.maxAmount.String())
 
 	rest := lipgloss.NewStyle().Italic(true).Render(m.mostExpensive.Restaurant)
 	
@@ -3,8 +3,8 @@ package zomato
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -17,6 +17,32 @@ type Client struct {
 	HTTPClient *http.Client
 	BaseURL    string
 	Cookie     string
+	Retry      RetryPolicy
+	// PageDelay is the pause between consecutive page or detail requests.
+	PageDelay time.Duration
+	// OnRetry, when set, is called before each retry.
+	OnRetry func(RetryEvent)
+	// OnRaw, when set, receives every successful response body so callers
+	// can archive it for re-parsing later.
+	OnRaw func(RawResponse)
+	// Location is the region timezone order dates are parsed in; nil means
+	// DefaultTimezone.
+	Location *time.Location
+}
+
+// Kinds of raw responses passed to Client.OnRaw.
+const (
+	RawOrders  = "orders"
+	RawDetails = "details"
+)
+
+// RawResponse is an unparsed API response body. Key is the page number for
+// RawOrders and the order hash ID for RawDetails.
+type RawResponse struct {
+	Kind      string
+	Key       string
+	FetchedAt time.Time
+	Body      []byte
 }
 
 func NewClient(cookie string) *Client {
@@ -24,6 +50,8 @@ func NewClient(cookie string) *Client {
 		HTTPClient: http.DefaultClient,
 		BaseURL:    "https://www.zomato.com",
 		Cookie:     cookie,
+		Retry:      DefaultRetryPolicy,
+		PageDelay:  DefaultPageDelay,
 	}
 }
 
@@ -38,19 +66,37 @@ type FetchProgress struct {
 	TotalOrders int
 }
 
+// FetchOptions controls how FetchOrdersWithOptions walks the order history.
+type FetchOptions struct {
+	// Known holds order IDs that are already stored locally. When set, the
+	// fetch stops after the first page whose orders are all known.
+	Known map[string]struct{}
+	// StartPage resumes a previously interrupted fetch; 0 means page 1.
+	StartPage int
+	Progress  func(FetchProgress)
+}
+
 func (c *Client) FetchOrdersWithProgress(ctx context.Context, progress func(FetchProgress)) ([]Order, error) {
+	return c.FetchOrdersWithOptions(ctx, FetchOptions{Progress: progress})
+}
+
+// FetchOrdersWithOptions fetches orders newest-first, one page at a time.
+// If a page fails after retries, the orders fetched so far are returned
+// together with a *PageError naming the page to resume from.
+func (c *Client) FetchOrdersWithOptions(ctx context.Context, opts FetchOptions) ([]Order, error) {
 	var all []Order
-	page := 1
+	page := max(opts.StartPage, 1)
 	seen := map[string]struct{}{}
 
 	for {
 		resp, err := c.fetchOrdersPage(ctx, page)
 		if err != nil {
-			return nil, err
+			return all, &PageError{Page: page, Err: err}
 		}
 
-		orders := ordersFromResponse(resp)
+		orders := ordersFromResponse(resp, c.location())
 		newCount := 0
+		unknownCount := 0
 		for _, order := range orders {
 			if order.ID == "" {
 				continue
@@ -59,13 +105,16 @@ func (c *Client) FetchOrdersWithProgress(ctx context.Context, progress func(Fetc
 				continue
 			}
 			seen[order.ID] = struct{}{}
+			if _, ok := opts.Known[order.ID]; !ok {
+				unknownCount++
+			}
 			all = append(all, order)
 			newCount++
 		}
 
 		totalPages := resp.Sections.OrderHistory.TotalPages
-		if progress != nil {
-			progress(FetchProgress{
+		if opts.Progress != nil {
+			opts.Progress(FetchProgress{
 				Page:        page,
 				TotalPages:  totalPages,
 				NewOrders:   newCount,
@@ -75,50 +124,21 @@ func (c *Client) FetchOrdersWithProgress(ctx context.Context, progress func(Fetc
 		if newCount == 0 {
 			break
 		}
+		if len(opts.Known) > 0 && unknownCount == 0 {
+			break
+		}
 		if totalPages == 0 || page >= totalPages {
 			break
 		}
-		time.Sleep(500 * time.Millisecond)
+		if err := sleep(ctx, c.PageDelay); err != nil {
+			return all, &PageError{Page: page + 1, Err: err}
+		}
 		page++
 	}
 
 	return all, nil
 }
 
-func (c *Client) DebugFetchFirstPageRaw(ctx context.Context) (string, error) {
-	endpoint, err := url.Parse(c.BaseURL + "/webroutes/user/orders")
-	if err != nil {
-		return "", err
-	}
-	query := endpoint.Query()
-	query.Set("page", "1")
-	endpoint.RawQuery = query.Encode()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return "", err
-	}
-	c.decorateRequest(req)
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("zomato orders request failed: %s", resp.Status)
-	}
-
-	// Just read the body and return it
-	var buf strings.Builder
-	_, err = io.Copy(&buf, resp.Body)
-	if err != nil {
-		return "", err
-	}
-	return buf.String(), nil
-}
-
 func (c *Client) CheckAuth(ctx context.Context) (bool, error) {
 	endpoint := c.BaseURL + "/webroutes/user/address"
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
@@ -133,13 +153,14 @@ func (c *Client) CheckAuth(ctx context.Context) (bool, error) {
 	}
 	defer resp.Body.Close()
 
-	switch resp.StatusCode {
-	case http.StatusOK:
+	err = checkResponse("auth status", resp)
+	switch {
+	case err == nil:
 		return true, nil
-	case http.StatusUnauthorized, http.StatusForbidden:
+	case errors.Is(err, ErrUnauthorized):
 		return false, nil
 	default:
-		return false, fmt.Errorf("auth status request failed: %s", resp.Status)
+		return false, err
 	}
 }
 
@@ -194,29 +215,57 @@ func (c *Client) fetchOrdersPage(ctx context.Context, page int) (ordersResponse,
 	}
 	endpoint.RawQuery = query.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	})
 	if err != nil {
 		return out, err
 	}
-	c.decorateRequest(req)
+	defer resp.Body.Close()
 
-	resp, err := c.HTTPClient.Do(req)
+	if err := checkResponse("orders", resp); err != nil {
+		return out, err
+	}
+
+	body, err := readBody("orders", resp)
 	if err != nil {
 		return out, err
 	}
-	defer resp.Body.Close()
+	c.record(RawOrders, strconv.Itoa(page), body)
+	return parseOrdersBody(body)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return out, fmt.Errorf("zomato orders request failed: %s", resp.Status)
+// ParseOrdersPage normalizes the orders in a raw order history page as
+// returned by the API, e.g. one read back from the raw archive. Order dates
+// are interpreted in loc, or DefaultTimezone when loc is nil.
+func ParseOrdersPage(body []byte, loc *time.Location) ([]Order, error) {
+	resp, err := parseOrdersBody(body)
+	if err != nil {
+		return nil, err
 	}
+	return ordersFromResponse(resp, orDefaultLocation(loc)), nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+func parseOrdersBody(body []byte) (ordersResponse, error) {
+	var out ordersResponse
+	if err := decodeBody("orders", body, &out); err != nil {
 		return out, err
 	}
-
+	var shape struct {
+		Sections map[string]json.RawMessage `json:"sections"`
+	}
+	if err := json.Unmarshal(body, &shape); err != nil || shape.Sections["SECTION_USER_ORDER_HISTORY"] == nil {
+		return out, schemaError("orders", body, "sections.SECTION_USER_ORDER_HISTORY")
+	}
 	return out, nil
 }
 
+func (c *Client) record(kind, key string, body []byte) {
+	if c.OnRaw != nil {
+		c.OnRaw(RawResponse{Kind: kind, Key: key, FetchedAt: time.Now(), Body: body})
+	}
+}
+
 func (c *Client) decorateRequest(req *http.Request) {
 	req.Header.Set("Cookie", c.Cookie)
 	req.Header.Set("Accept", "application/json, text/plain, */*")
@@ -224,7 +273,7 @@ func (c *Client) decorateRequest(req *http.Request) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)")
 }
 
-func ordersFromResponse(resp ordersResponse) []Order {
+func ordersFromResponse(resp ordersResponse, loc *time.Location) []Order {
 	if len(resp.Sections.OrderHistory.Entities) == 0 {
 		return nil
 	}
@@ -239,13 +288,13 @@ func ordersFromResponse(resp ordersResponse) []Order {
 			if !ok {
 				continue
 			}
-			orders = append(orders, normalizeOrder(raw))
+			orders = append(orders, normalizeOrder(raw, loc))
 		}
 	}
 	return orders
 }
 
-func normalizeOrder(raw orderEntity) Order {
+func normalizeOrder(raw orderEntity, loc *time.Location) Order {
 	status := strings.TrimSpace(raw.DeliveryDetails.DeliveryLabel)
 	if status == "" {
 		status = strings.TrimSpace(raw.DeliveryDetails.DeliveryMessage)
@@ -254,17 +303,21 @@ func normalizeOrder(raw orderEntity) Order {
 		status = fmt.Sprintf("Status %d", raw.Status)
 	}
 
+	// An unparseable total is kept as zero rather than dropping the order.
+	total, _ := ParseMoney(raw.TotalCost, "")
+
 	return Order{
 		ID:         strconv.FormatInt(raw.OrderID, 10),
+		HashID:     strings.TrimSpace(raw.HashID),
 		Restaurant: strings.TrimSpace(raw.ResInfo.Name),
 		Status:     status,
-		PlacedAt:   parseOrderDate(raw.OrderDate),
-		Total:      strings.TrimSpace(raw.TotalCost),
+		PlacedAt:   parseOrderDate(raw.OrderDate, loc),
+		Total:      total,
 		Items:      parseItems(raw.DishString),
 	}
 }
 
-func parseOrderDate(input string) time.Time {
+func parseOrderDate(input string, loc *time.Location) time.Time {
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return time.Time{}
@@ -280,7 +333,7 @@ func parseOrderDate(input string) time.Time {
 		time.RFC3339,
 	}
 	for _, layout := range layouts {
-		if parsed, err := time.ParseInLocation(layout, input, time.Local); err == nil {
+		if parsed, err := time.ParseInLocation(layout, input, loc); err == nil {
 			return parsed
 		}
 	}
@@ -34,9 +34,13 @@ func TestParseOrderDate(t *testing.T) {
 		},
 	}
 
+	ist, err := LoadLocation(DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got := parseOrderDate(tt.input)
+			got := parseOrderDate(tt.input, ist)
 			if tt.wantErr {
 				if !got.IsZero() {
 					t.Errorf("parseOrderDate(%q) = %v, want zero time", tt.input, got)
@@ -44,22 +48,19 @@ func TestParseOrderDate(t *testing.T) {
 				return
 			}
 
-			// Construct expected time in Local timezone to match implementation
 			wantTime, err := time.Parse(time.RFC3339, tt.wantTime)
 			if err != nil {
 				t.Fatalf("bad test case time: %v", err)
 			}
-			// Convert wantTime (UTC) to Local to match what parseOrderDate returns (Local)
-			// effectively we want the same wall clock time if the input didn't specify zone
-			// But wait, the input strings don't have zone. So parseOrderDate interprets them as Local.
-			// The validation string "2023-01-02T15:04:00Z" is UTC.
-			// We should construct the expected time using Date() in Local.
-			
-			want := time.Date(wantTime.Year(), wantTime.Month(), wantTime.Day(), wantTime.Hour(), wantTime.Minute(), wantTime.Second(), 0, time.Local)
+			// Inputs carry no zone, so the wall clock is read in the region zone.
+			want := time.Date(wantTime.Year(), wantTime.Month(), wantTime.Day(), wantTime.Hour(), wantTime.Minute(), wantTime.Second(), 0, ist)
 			
 			if !got.Equal(want) {
 				t.Errorf("parseOrderDate(%q) = %v, want %v", tt.input, got, want)
 			}
+			if got.Format(time.RFC3339)[19:] != "+05:30" {
+				t.Errorf("parseOrderDate(%q) offset = %s, want +05:30", tt.input, got.Format(time.RFC3339))
+			}
 		})
 	}
 }
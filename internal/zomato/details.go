@@ -0,0 +1,217 @@
+package zomato
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The order detail endpoint is the one the order summary page on the web
+// app calls; it is keyed by the order's hashId rather than its numeric ID.
+const orderDetailsPath = "/webroutes/order/details"
+
+type orderDetailsResponse struct {
+	Details struct {
+		Order struct {
+			Items []struct {
+				Name      string     `json:"name"`
+				Quantity  int        `json:"quantity"`
+				UnitCost  flexAmount `json:"unitCost"`
+				TotalCost flexAmount `json:"totalCost"`
+			} `json:"items"`
+			BillItems []struct {
+				Title string     `json:"title"`
+				Value flexAmount `json:"value"`
+			} `json:"billItems"`
+			PromoCode     string `json:"promoCode"`
+			PaymentMethod string `json:"paymentMethod"`
+		} `json:"order"`
+	} `json:"details"`
+}
+
+// flexAmount accepts amounts sent either as JSON numbers in major units or
+// as display strings such as "₹1,234.50" or "-₹40".
+type flexAmount struct {
+	number *float64
+	text   string
+}
+
+func (a *flexAmount) UnmarshalJSON(data []byte) error {
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		a.number = &n
+		return nil
+	}
+	return json.Unmarshal(data, &a.text)
+}
+
+// money resolves the amount, using currency when it carries no marker.
+func (a flexAmount) money(currency string) Money {
+	if a.number != nil {
+		return MoneyFromFloat(*a.number, currency)
+	}
+	m, err := ParseMoney(a.text, currency)
+	if err != nil {
+		return Money{Currency: currency}
+	}
+	return m
+}
+
+// FetchOrderDetails fetches the bill breakdown and priced items of one order.
+// Amounts without a currency marker are taken to be in currency.
+func (c *Client) FetchOrderDetails(ctx context.Context, hashID, currency string) (OrderDetails, []OrderItem, error) {
+	var details OrderDetails
+
+	endpoint, err := url.Parse(c.BaseURL + orderDetailsPath)
+	if err != nil {
+		return details, nil, err
+	}
+	query := endpoint.Query()
+	query.Set("hashId", hashID)
+	endpoint.RawQuery = query.Encode()
+
+	resp, err := c.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	})
+	if err != nil {
+		return details, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse("order details", resp); err != nil {
+		return details, nil, err
+	}
+
+	body, err := readBody("order details", resp)
+	if err != nil {
+		return details, nil, err
+	}
+	c.record(RawDetails, hashID, body)
+	details, items, err := ParseOrderDetails(body, currency)
+	if err != nil {
+		return details, nil, err
+	}
+	details.FetchedAt = time.Now()
+	return details, items, nil
+}
+
+// ParseOrderDetails normalizes a raw order details response. Amounts without
+// a currency marker are taken to be in currency.
+func ParseOrderDetails(body []byte, currency string) (OrderDetails, []OrderItem, error) {
+	var out orderDetailsResponse
+	if err := decodeBody("order details", body, &out); err != nil {
+		return OrderDetails{}, nil, err
+	}
+	details, items := normalizeDetails(out, currency)
+	return details, items, nil
+}
+
+// FetchDetails fetches details for every order that has a hash ID but no
+// details yet, returning only the orders it enriched. On error the orders
+// enriched so far are returned alongside it.
+func (c *Client) FetchDetails(ctx context.Context, orders []Order, progress func(done, total int)) ([]Order, error) {
+	var pending []Order
+	for _, order := range orders {
+		if order.Details == nil && order.HashID != "" {
+			pending = append(pending, order)
+		}
+	}
+
+	var enriched []Order
+	for i, order := range pending {
+		if i > 0 {
+			if err := sleep(ctx, c.PageDelay); err != nil {
+				return enriched, err
+			}
+		}
+		details, items, err := c.FetchOrderDetails(ctx, order.HashID, order.Total.Currency)
+		if err != nil {
+			return enriched, fmt.Errorf("order %s: %w", order.ID, err)
+		}
+		order.Details = &details
+		if len(items) > 0 {
+			order.Items = items
+		}
+		enriched = append(enriched, order)
+		if progress != nil {
+			progress(i+1, len(pending))
+		}
+	}
+	return enriched, nil
+}
+
+func normalizeDetails(resp orderDetailsResponse, currency string) (OrderDetails, []OrderItem) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	raw := resp.Details.Order
+	zero := Money{Currency: currency}
+	details := OrderDetails{
+		ItemTotal:       zero,
+		Taxes:           zero,
+		DeliveryFee:     zero,
+		PackagingCharge: zero,
+		Tip:             zero,
+		Discount:        zero,
+		PromoCode:       strings.TrimSpace(raw.PromoCode),
+		PaymentMethod:   strings.TrimSpace(raw.PaymentMethod),
+	}
+
+	for _, bill := range raw.BillItems {
+		amount := bill.Value.money(currency)
+		title := strings.ToLower(bill.Title)
+		switch {
+		case strings.Contains(title, "item total") || strings.Contains(title, "subtotal"):
+			details.ItemTotal = details.ItemTotal.Add(amount)
+		case strings.Contains(title, "tax") || strings.Contains(title, "gst"):
+			details.Taxes = details.Taxes.Add(amount)
+		case strings.Contains(title, "tip"):
+			details.Tip = details.Tip.Add(amount)
+		case strings.Contains(title, "packag"):
+			details.PackagingCharge = details.PackagingCharge.Add(amount)
+		case strings.Contains(title, "delivery"):
+			details.DeliveryFee = details.DeliveryFee.Add(amount)
+		case strings.Contains(title, "discount") || strings.Contains(title, "promo") || strings.Contains(title, "coupon") || strings.Contains(title, "offer"):
+			if amount.Amount < 0 {
+				amount.Amount = -amount.Amount
+			}
+			details.Discount = details.Discount.Add(amount)
+		}
+	}
+
+	items := make([]OrderItem, 0, len(raw.Items))
+	itemSum := zero
+	for _, it := range raw.Items {
+		name := strings.TrimSpace(it.Name)
+		if name == "" {
+			continue
+		}
+		qty := it.Quantity
+		if qty <= 0 {
+			qty = 1
+		}
+		unit := it.UnitCost.money(currency)
+		total := it.TotalCost.money(currency)
+		if unit.IsZero() && total.Amount > 0 {
+			unit = total.Div(qty)
+		}
+		if total.IsZero() && unit.Amount > 0 {
+			total = unit.Mul(qty)
+		}
+		itemSum = itemSum.Add(total)
+		items = append(items, OrderItem{
+			Name:       name,
+			Quantity:   qty,
+			UnitPrice:  unit,
+			TotalPrice: total,
+		})
+	}
+	if details.ItemTotal.IsZero() {
+		details.ItemTotal = itemSum
+	}
+	return details, items
+}
@@ -0,0 +1,113 @@
+package zomato
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_FetchOrderDetails(t *testing.T) {
+	mockResponse := `
+{
+  "details": {
+    "order": {
+      "items": [
+        { "name": "Chicken Biryani", "quantity": 2, "unitCost": "₹250", "totalCost": "₹500" },
+        { "name": "Gulab Jamun", "quantity": 1, "totalCost": 80 }
+      ],
+      "billItems": [
+        { "title": "Item Total", "value": "₹580" },
+        { "title": "Taxes & Charges", "value": "₹29.50" },
+        { "title": "Delivery partner fee", "value": "₹40" },
+        { "title": "Packaging charges", "value": 20 },
+        { "title": "Delivery Tip", "value": "₹30" },
+        { "title": "Promo discount", "value": "-₹100" }
+      ],
+      "promoCode": "WELCOME50",
+      "paymentMethod": "UPI"
+    }
+  }
+}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/webroutes/order/details" {
+			t.Errorf("Expected path /webroutes/order/details, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("hashId"); got != "abc123" {
+			t.Errorf("hashId = %q, want abc123", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockResponse))
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-cookie")
+	client.BaseURL = ts.URL
+
+	details, items, err := client.FetchOrderDetails(context.Background(), "abc123", "INR")
+	if err != nil {
+		t.Fatalf("FetchOrderDetails failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+	if items[0].UnitPrice != inr(25000) || items[0].TotalPrice != inr(50000) {
+		t.Errorf("items[0] = %+v, want unit 250 total 500", items[0])
+	}
+	if items[1].UnitPrice != inr(8000) {
+		t.Errorf("items[1].UnitPrice = %v, want 80", items[1].UnitPrice)
+	}
+
+	want := OrderDetails{
+		ItemTotal:       inr(58000),
+		Taxes:           inr(2950),
+		DeliveryFee:     inr(4000),
+		PackagingCharge: inr(2000),
+		Tip:             inr(3000),
+		Discount:        inr(10000),
+		PromoCode:       "WELCOME50",
+		PaymentMethod:   "UPI",
+	}
+	details.FetchedAt = want.FetchedAt
+	if details != want {
+		t.Errorf("details = %+v, want %+v", details, want)
+	}
+}
+
+func TestClient_FetchDetails_SkipsCached(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"details":{"order":{"items":[{"name":"Burger","quantity":1,"unitCost":150}]}}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-cookie")
+	client.BaseURL = ts.URL
+
+	orders := []Order{
+		{ID: "1", HashID: "h1"},
+		{ID: "2", HashID: "h2", Details: &OrderDetails{ItemTotal: inr(10000)}},
+		{ID: "3"},
+	}
+	enriched, err := client.FetchDetails(context.Background(), orders, nil)
+	if err != nil {
+		t.Fatalf("FetchDetails failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+	if len(enriched) != 1 || enriched[0].ID != "1" {
+		t.Fatalf("enriched = %+v, want only order 1", enriched)
+	}
+	if enriched[0].Details.ItemTotal != inr(15000) {
+		t.Errorf("ItemTotal = %v, want 150", enriched[0].Details.ItemTotal)
+	}
+}
+
+func inr(paise int64) Money {
+	return Money{Amount: paise, Currency: "INR"}
+}
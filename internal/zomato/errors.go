@@ -0,0 +1,149 @@
+package zomato
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors returned by the client, wrapped in an *APIError. Match them with
+// errors.Is.
+var (
+	// ErrUnauthorized means the session cookie is missing, expired or revoked.
+	ErrUnauthorized = errors.New("zomato session is not authorized")
+	// ErrRateLimited means Zomato kept answering 429 after all retries.
+	ErrRateLimited = errors.New("rate limited by zomato")
+	// ErrBlocked means an HTML page (captcha or bot challenge) was served
+	// instead of the JSON API response.
+	ErrBlocked = errors.New("request blocked by a zomato challenge page")
+	// ErrSchemaChanged means the response was JSON-shaped but no longer
+	// matches what the client knows how to parse.
+	ErrSchemaChanged = errors.New("unexpected zomato response format")
+)
+
+const snippetLimit = 200
+
+// APIError describes a failed Zomato request.
+type APIError struct {
+	// Op names the request, e.g. "orders" or "order details".
+	Op         string
+	StatusCode int
+	Status     string
+	// Snippet holds the start of the response body for diagnostics.
+	Snippet string
+	// Err is one of the sentinel errors above, or nil for other failures.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "zomato %s request failed", e.Op)
+	if e.Status != "" {
+		fmt.Fprintf(&b, ": %s", e.Status)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	if e.Snippet != "" && errors.Is(e.Err, ErrSchemaChanged) {
+		fmt.Fprintf(&b, " (response: %s)", e.Snippet)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// checkResponse turns a non-200 response into an *APIError, reading the
+// start of the body to tell challenge pages from plain auth failures.
+func checkResponse(op string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &APIError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Snippet:    snippet(body),
+	}
+	switch {
+	case looksLikeChallenge(resp, body):
+		apiErr.Err = ErrBlocked
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		apiErr.Err = ErrUnauthorized
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.Err = ErrRateLimited
+	}
+	return apiErr
+}
+
+// readBody reads a 200 response, reporting HTML pages as ErrBlocked.
+func readBody(op string, resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if isHTML(resp.Header.Get("Content-Type"), body) {
+		return nil, &APIError{Op: op, Snippet: snippet(body), Err: ErrBlocked}
+	}
+	return body, nil
+}
+
+// decodeBody unmarshals body into out, reporting failures as
+// ErrSchemaChanged.
+func decodeBody(op string, body []byte, out any) error {
+	if err := json.Unmarshal(body, out); err != nil {
+		return &APIError{Op: op, Snippet: snippet(body), Err: fmt.Errorf("%w: %v", ErrSchemaChanged, err)}
+	}
+	return nil
+}
+
+// schemaError reports a decodable response that lacks a required field.
+func schemaError(op string, body []byte, missing string) error {
+	return &APIError{
+		Op:      op,
+		Snippet: snippet(body),
+		Err:     fmt.Errorf("%w: missing %s", ErrSchemaChanged, missing),
+	}
+}
+
+func isHTML(contentType string, body []byte) bool {
+	if strings.Contains(contentType, "text/html") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '<'
+}
+
+var challengeMarkers = []string{"captcha", "challenge", "cf-ray", "access denied", "are you a robot", "akamai"}
+
+func looksLikeChallenge(resp *http.Response, body []byte) bool {
+	if !isHTML(resp.Header.Get("Content-Type"), body) {
+		return false
+	}
+	lower := strings.ToLower(string(body))
+	for _, marker := range challengeMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) <= snippetLimit {
+		return s
+	}
+	cut := snippetLimit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
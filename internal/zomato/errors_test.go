@@ -0,0 +1,65 @@
+package zomato
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_FetchOrders_ErrorTaxonomy(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        error
+	}{
+		{"expired cookie", http.StatusUnauthorized, "application/json", `{"message":"unauthorized"}`, ErrUnauthorized},
+		{"forbidden json", http.StatusForbidden, "application/json", `{}`, ErrUnauthorized},
+		{"captcha", http.StatusForbidden, "text/html", `<html><body>Please complete the captcha</body></html>`, ErrBlocked},
+		{"challenge with 200", http.StatusOK, "text/html", `<!DOCTYPE html><html>checking your browser</html>`, ErrBlocked},
+		{"rate limited", http.StatusTooManyRequests, "application/json", `{}`, ErrRateLimited},
+		{"not json", http.StatusOK, "application/json", `{"sections": [`, ErrSchemaChanged},
+		{"missing section", http.StatusOK, "application/json", `{"status":"success","sections":{}}`, ErrSchemaChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			client := fastClient(ts.URL)
+			client.Retry.MaxRetries = 0
+
+			_, err := client.FetchOrders(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %T, want *APIError", err)
+			}
+			if tt.want == ErrSchemaChanged && !strings.Contains(err.Error(), "sections") {
+				t.Errorf("schema error %q should include the payload snippet", err)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("₹", snippetLimit)
+	got := snippet([]byte(long))
+	if !strings.HasSuffix(got, "…") || len(got) > snippetLimit+len("…") {
+		t.Errorf("snippet length = %d, want truncated to %d bytes", len(got), snippetLimit)
+	}
+	if got := snippet([]byte("  a \n\t b ")); got != "a b" {
+		t.Errorf("snippet = %q, want whitespace collapsed", got)
+	}
+}
@@ -2,9 +2,11 @@ package zomato
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestClient_FetchOrders(t *testing.T) {
@@ -68,8 +70,8 @@ func TestClient_FetchOrders(t *testing.T) {
 	if got.Restaurant != "Burger King" {
 		t.Errorf("Restaurant = %q, want Burger King", got.Restaurant)
 	}
-	if got.Total != "₹150" {
-		t.Errorf("Total = %q, want ₹150", got.Total)
+	if got.Total != inr(15000) {
+		t.Errorf("Total = %v, want ₹150.00", got.Total)
 	}
 }
 
@@ -81,6 +83,7 @@ func TestClient_FetchOrders_Error(t *testing.T) {
 
 	client := NewClient("test-cookie")
 	client.BaseURL = ts.URL
+	client.Retry = RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}
 
 	_, err := client.FetchOrders(context.Background())
 	if err == nil {
@@ -122,3 +125,96 @@ func TestClient_CheckAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_FetchOrdersWithOptions_StopsAtKnown(t *testing.T) {
+	pages := map[string]string{
+		"1": pageJSON(1, 3, 3, "Page One"),
+		"2": pageJSON(2, 3, 2, "Page Two"),
+		"3": pageJSON(3, 3, 1, "Page Three"),
+	}
+	var requested []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		requested = append(requested, page)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pages[page]))
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-cookie")
+	client.BaseURL = ts.URL
+
+	orders, err := client.FetchOrdersWithOptions(context.Background(), FetchOptions{
+		Known: map[string]struct{}{"2": {}},
+	})
+	if err != nil {
+		t.Fatalf("FetchOrdersWithOptions failed: %v", err)
+	}
+
+	if len(requested) != 2 {
+		t.Fatalf("Requested pages %v, want [1 2]", requested)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("Got %d orders, want 2", len(orders))
+	}
+	if orders[0].ID != "3" || orders[1].ID != "2" {
+		t.Errorf("Order IDs = %s, %s; want 3, 2", orders[0].ID, orders[1].ID)
+	}
+}
+
+func pageJSON(page, totalPages int, id int64, restaurant string) string {
+	return fmt.Sprintf(`{
+  "sections": {
+    "SECTION_USER_ORDER_HISTORY": {
+      "currentPage": %d,
+      "totalPages": %d,
+      "entities": [{ "entity_type": "ORDER", "entity_ids": [%d] }]
+    }
+  },
+  "entities": {
+    "ORDER": {
+      "%d": {
+        "orderId": %d,
+        "totalCost": "₹100",
+        "orderDate": "January 1, 2024 12:00 PM",
+        "dishString": "1 x Burger",
+        "deliveryDetails": { "deliveryLabel": "Delivered" },
+        "resInfo": { "name": %q }
+      }
+    }
+  }
+}`, page, totalPages, id, id, id, restaurant)
+}
+
+func TestClient_OnRawMatchesParseOrdersPage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pageJSON(1, 1, 7, "Archive Cafe")))
+	}))
+	defer ts.Close()
+
+	client := NewClient("test-cookie")
+	client.BaseURL = ts.URL
+	var raws []RawResponse
+	client.OnRaw = func(raw RawResponse) { raws = append(raws, raw) }
+
+	fetched, err := client.FetchOrders(context.Background())
+	if err != nil {
+		t.Fatalf("FetchOrders failed: %v", err)
+	}
+	if len(raws) != 1 || raws[0].Kind != RawOrders || raws[0].Key != "1" {
+		t.Fatalf("raw responses = %+v, want one orders page 1", raws)
+	}
+
+	reparsed, err := ParseOrdersPage(raws[0].Body, client.Location)
+	if err != nil {
+		t.Fatalf("ParseOrdersPage failed: %v", err)
+	}
+	if len(reparsed) != 1 || reparsed[0].ID != fetched[0].ID || reparsed[0].Restaurant != "Archive Cafe" {
+		t.Errorf("reparsed = %+v, want %+v", reparsed, fetched)
+	}
+}
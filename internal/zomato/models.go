@@ -4,14 +4,40 @@ import "time"
 
 type Order struct {
 	ID         string      `json:"id"`
+	HashID     string      `json:"hash_id,omitempty"`
 	Restaurant string      `json:"restaurant"`
 	Status     string      `json:"status"`
 	PlacedAt   time.Time   `json:"placed_at"`
-	Total      string      `json:"total"`
+	Total      Money       `json:"total"`
 	Items      []OrderItem `json:"items"`
+
+	// Details holds the bill breakdown from the order detail endpoint. It is
+	// nil until the order has been fetched with `sync --details`.
+	Details *OrderDetails `json:"details,omitempty"`
+
+	// FirstSeen and LastUpdated are maintained by the local store.
+	FirstSeen   time.Time `json:"first_seen,omitzero"`
+	LastUpdated time.Time `json:"last_updated,omitzero"`
 }
 
 type OrderItem struct {
 	Name     string `json:"name"`
 	Quantity int    `json:"quantity"`
+	// UnitPrice and TotalPrice are only known for orders with Details.
+	UnitPrice  Money `json:"unit_price,omitzero"`
+	TotalPrice Money `json:"total_price,omitzero"`
+}
+
+// OrderDetails is the bill breakdown of a single order. Amounts are in the
+// order's currency; Discount is a positive amount taken off the bill.
+type OrderDetails struct {
+	ItemTotal       Money     `json:"item_total"`
+	Taxes           Money     `json:"taxes"`
+	DeliveryFee     Money     `json:"delivery_fee"`
+	PackagingCharge Money     `json:"packaging_charge"`
+	Tip             Money     `json:"tip"`
+	Discount        Money     `json:"discount"`
+	PromoCode       string    `json:"promo_code,omitempty"`
+	PaymentMethod   string    `json:"payment_method,omitempty"`
+	FetchedAt       time.Time `json:"fetched_at"`
 }
@@ -0,0 +1,302 @@
+package zomato
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed for amounts that carry no currency marker.
+const DefaultCurrency = "INR"
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are
+// added together.
+var ErrCurrencyMismatch = errors.New("amounts in different currencies")
+
+// Money is an amount in integer minor units (paise, cents, fils) of an
+// ISO 4217 currency, so sums never drift the way floats do.
+type Money struct {
+	Amount   int64  `json:"amount_minor"`
+	Currency string `json:"currency"`
+}
+
+type currencyInfo struct {
+	Symbol   string
+	Exponent int
+}
+
+var currencies = map[string]currencyInfo{
+	"INR": {"₹", 2},
+	"AED": {"AED ", 2},
+	"USD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"SAR": {"SAR ", 2},
+	"QAR": {"QAR ", 2},
+	"LKR": {"LKR ", 2},
+	"SGD": {"S$", 2},
+	"AUD": {"A$", 2},
+	"NZD": {"NZ$", 2},
+	"MYR": {"RM", 2},
+	"PHP": {"₱", 2},
+	"TRY": {"₺", 2},
+	"IDR": {"Rp", 0},
+	"LBP": {"LBP ", 0},
+	"JPY": {"¥", 0},
+}
+
+// currencyAliases maps the markers seen in Zomato display strings to codes.
+var currencyAliases = map[string]string{
+	"₹": "INR", "rs": "INR", "rs.": "INR", "inr": "INR",
+	"$": "USD", "us$": "USD",
+	"€": "EUR", "£": "GBP",
+	"aed": "AED", "د.إ": "AED", "dhs": "AED",
+	"s$": "SGD", "a$": "AUD", "nz$": "NZD",
+	"rm": "MYR", "₱": "PHP", "₺": "TRY", "tl": "TRY",
+	"rp": "IDR", "¥": "JPY",
+}
+
+var numberPattern = regexp.MustCompile(`[0-9][0-9.,]*`)
+
+// CurrencyCode maps a currency marker such as "₹", "Rs." or "aed" to its
+// ISO code. Unknown markers are returned upper-cased.
+func CurrencyCode(marker string) string {
+	marker = strings.TrimSpace(marker)
+	if marker == "" {
+		return ""
+	}
+	if code, ok := currencyAliases[strings.ToLower(marker)]; ok {
+		return code
+	}
+	if strings.Contains(marker, "₹") {
+		return "INR"
+	}
+	return strings.ToUpper(marker)
+}
+
+// CurrencySymbol returns the display prefix for a currency code.
+func CurrencySymbol(code string) string {
+	if info, ok := currencies[code]; ok {
+		return info.Symbol
+	}
+	if code == "" {
+		return ""
+	}
+	return code + " "
+}
+
+func exponent(code string) int {
+	if info, ok := currencies[code]; ok {
+		return info.Exponent
+	}
+	return 2
+}
+
+// ParseMoney parses display amounts such as "₹1,234.50", "Rs. 100",
+// "AED 45" or "-$3". Amounts without a currency marker use fallback, or
+// DefaultCurrency when fallback is empty.
+func ParseMoney(input, fallback string) (Money, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Money{}, fmt.Errorf("empty amount")
+	}
+
+	loc := numberPattern.FindStringIndex(input)
+	if loc == nil {
+		return Money{}, fmt.Errorf("no amount in %q", input)
+	}
+	number := strings.TrimRight(input[loc[0]:loc[1]], ".,")
+	negative := strings.Contains(input[:loc[0]], "-")
+	marker := strings.TrimSpace(input[:loc[0]] + " " + input[loc[1]:])
+	marker = strings.TrimSpace(strings.ReplaceAll(marker, "-", ""))
+
+	currency := CurrencyCode(marker)
+	if currency == "" {
+		currency = fallback
+	}
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	amount, err := parseMinor(number, exponent(currency))
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", input, err)
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// parseMinor converts a decimal string with optional grouping commas into
+// minor units, rounding half away from zero past the currency's exponent.
+// A lone comma followed by one or two digits is read as a decimal comma.
+func parseMinor(s string, exp int) (int64, error) {
+	if !strings.Contains(s, ".") {
+		if i := strings.LastIndex(s, ","); i >= 0 && strings.Count(s, ",") == 1 && len(s)-i-1 <= 2 && len(s)-i-1 > 0 {
+			s = s[:i] + "." + s[i+1:]
+		}
+	}
+	s = strings.ReplaceAll(s, ",", "")
+	whole, frac, _ := strings.Cut(s, ".")
+	if strings.Contains(frac, ".") {
+		return 0, fmt.Errorf("more than one decimal point")
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	roundUp := false
+	if len(frac) > exp {
+		roundUp = frac[exp] >= '5'
+		frac = frac[:exp]
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	value, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if roundUp {
+		value++
+	}
+	return value, nil
+}
+
+// MoneyFromFloat converts a major-unit amount, rounding to minor units.
+func MoneyFromFloat(value float64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	scale := math.Pow10(exponent(currency))
+	return Money{Amount: int64(math.Round(value * scale)), Currency: currency}
+}
+
+// Float returns the amount in major units, for ratios and display only.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / math.Pow10(exponent(m.Currency))
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns m + other. Both must share a currency; a zero-value m adopts
+// other's currency so sums can start from Money{}. Add panics when the
+// currencies differ: callers summing amounts that may not share one use
+// CheckedAdd.
+func (m Money) Add(other Money) Money {
+	sum, err := m.CheckedAdd(other)
+	if err != nil {
+		panic(err)
+	}
+	return sum
+}
+
+// CheckedAdd is Add for amounts that may be in different currencies, which
+// it reports with an error wrapping ErrCurrencyMismatch.
+func (m Money) CheckedAdd(other Money) (Money, error) {
+	if m.Currency != "" && other.Currency != "" && m.Currency != other.Currency {
+		return m, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	if m.Currency == "" {
+		m.Currency = other.Currency
+	}
+	m.Amount += other.Amount
+	return m, nil
+}
+
+// Mul returns m multiplied by n.
+func (m Money) Mul(n int) Money {
+	m.Amount *= int64(n)
+	return m
+}
+
+// Div returns m divided by n, rounded half away from zero.
+func (m Money) Div(n int) Money {
+	if n == 0 {
+		return Money{Currency: m.Currency}
+	}
+	q := m.Amount / int64(n)
+	r := m.Amount % int64(n)
+	if 2*abs64(r) >= int64(abs(n)) {
+		if (m.Amount < 0) != (n < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Money{Amount: q, Currency: m.Currency}
+}
+
+// Number formats the amount in major units without a currency symbol.
+func (m Money) Number() string {
+	exp := exponent(m.Currency)
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, exp, amount%scale)
+}
+
+// String formats the amount with its currency symbol, e.g. "₹150.00".
+func (m Money) String() string {
+	number := m.Number()
+	if strings.HasPrefix(number, "-") {
+		return "-" + CurrencySymbol(m.Currency) + number[1:]
+	}
+	return CurrencySymbol(m.Currency) + number
+}
+
+// UnmarshalJSON reads the {"amount_minor", "currency"} form as well as
+// legacy display strings ("₹150") and bare major-unit numbers.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if strings.TrimSpace(s) == "" {
+			*m = Money{}
+			return nil
+		}
+		// Unparseable legacy strings read as zero rather than failing the
+		// whole store load, matching how they were summed before.
+		parsed, _ := ParseMoney(s, "")
+		*m = parsed
+		return nil
+	}
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*m = MoneyFromFloat(n, DefaultCurrency)
+		return nil
+	}
+	type plain Money
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*m = Money(p)
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,116 @@
+package zomato
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{"₹123.45", Money{12345, "INR"}},
+		{"₹1,23,456", Money{12345600, "INR"}},
+		{"Rs. 100", Money{10000, "INR"}},
+		{"1,234.56", Money{123456, "INR"}},
+		{"$50", Money{5000, "USD"}},
+		{"AED 45.5", Money{4550, "AED"}},
+		{"45 AED", Money{4500, "AED"}},
+		{"-₹40", Money{-4000, "INR"}},
+		{"12,50", Money{1250, "INR"}},
+		{"₹0.125", Money{13, "INR"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input, "")
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "invalid", "₹"} {
+		if _, err := ParseMoney(input, ""); err == nil {
+			t.Errorf("ParseMoney(%q) expected error", input)
+		}
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	var sum Money
+	for i := 0; i < 10; i++ {
+		sum = sum.Add(Money{10, "INR"})
+	}
+	if sum != (Money{100, "INR"}) {
+		t.Errorf("sum = %+v, want 100 paise", sum)
+	}
+	if got := (Money{100, "INR"}).Div(3); got.Amount != 33 {
+		t.Errorf("Div(3) = %d, want 33", got.Amount)
+	}
+	if got := (Money{200, "INR"}).Div(3); got.Amount != 67 {
+		t.Errorf("Div(3) = %d, want 67", got.Amount)
+	}
+	if got := (Money{-5, "INR"}).Div(2); got.Amount != -3 {
+		t.Errorf("Div(2) = %d, want -3", got.Amount)
+	}
+	if got := (Money{123456, "INR"}).String(); got != "₹1234.56" {
+		t.Errorf("String = %q, want ₹1234.56", got)
+	}
+	if got := (Money{-4000, "AED"}).String(); got != "-AED 40.00" {
+		t.Errorf("String = %q, want -AED 40.00", got)
+	}
+	if got := (Money{1500, "JPY"}).String(); got != "¥1500" {
+		t.Errorf("String = %q, want ¥1500", got)
+	}
+}
+
+func TestMoney_AddCurrencyMismatch(t *testing.T) {
+	inr, aed := Money{100, "INR"}, Money{100, "AED"}
+	if _, err := inr.CheckedAdd(aed); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("CheckedAdd(INR, AED) err = %v, want ErrCurrencyMismatch", err)
+	}
+	if got, err := (Money{}).CheckedAdd(aed); err != nil || got != aed {
+		t.Errorf("CheckedAdd(zero, AED) = %+v, %v; want %+v", got, err, aed)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Add(INR, AED) did not panic")
+		}
+	}()
+	inr.Add(aed)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{`"₹150"`, Money{15000, "INR"}},
+		{`""`, Money{}},
+		{`"Free"`, Money{}},
+		{`99.5`, Money{9950, "INR"}},
+		{`{"amount_minor": 4550, "currency": "AED"}`, Money{4550, "AED"}},
+	}
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+
+	data, err := json.Marshal(Money{15000, "INR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount_minor":15000,"currency":"INR"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+}
@@ -0,0 +1,171 @@
+package zomato
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried after rate limiting, server
+// errors and network timeouts.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on each
+	// subsequent retry up to MaxDelay. A random jitter of up to half the
+	// delay is subtracted so concurrent clients don't retry in lockstep.
+	BaseDelay time.Duration
+	// MaxDelay caps every wait, including one asked for by Retry-After.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 4,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// DefaultPageDelay is the pause between consecutive page or detail requests.
+const DefaultPageDelay = 500 * time.Millisecond
+
+// RetryEvent describes a retry that is about to happen.
+type RetryEvent struct {
+	Attempt int
+	Wait    time.Duration
+	Reason  string
+}
+
+// PageError reports the page a paginated fetch failed on, so the caller can
+// resume from it later.
+type PageError struct {
+	Page int
+	Err  error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d: %v", e.Page, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// do sends the request built by newRequest, retrying according to c.Retry.
+// The request is rebuilt for every attempt. The caller owns the returned
+// response body.
+func (c *Client) do(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		c.decorateRequest(req)
+
+		resp, err := c.HTTPClient.Do(req)
+		var wait time.Duration
+		var reason string
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !retryableError(err) {
+				return nil, err
+			}
+			reason = err.Error()
+		case retryableStatus(resp.StatusCode):
+			reason = resp.Status
+			wait = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if c.Retry.MaxDelay > 0 && wait > c.Retry.MaxDelay {
+				wait = c.Retry.MaxDelay
+			}
+		default:
+			return resp, nil
+		}
+
+		if attempt >= c.Retry.MaxRetries {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if wait <= 0 {
+			wait = c.Retry.backoff(attempt)
+		}
+		if c.OnRetry != nil {
+			c.OnRetry(RetryEvent{Attempt: attempt + 1, Wait: wait, Reason: reason})
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 0; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(rand.Int64N(half))
+	}
+	return delay
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+func retryableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// retryAfter parses a Retry-After header given either as seconds or as an
+// HTTP date. It returns 0 when the header is missing or unparseable.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package zomato
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func fastClient(url string) *Client {
+	client := NewClient("test-cookie")
+	client.BaseURL = url
+	client.Retry = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	client.PageDelay = 0
+	return client
+}
+
+func TestClient_RetriesRateLimitAndServerErrors(t *testing.T) {
+	statuses := []int{http.StatusTooManyRequests, http.StatusBadGateway}
+	attempts := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[attempts-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pageJSON(1, 1, 1, "Retry Diner")))
+	}))
+	defer ts.Close()
+
+	client := fastClient(ts.URL)
+	var events []RetryEvent
+	client.OnRetry = func(e RetryEvent) { events = append(events, e) }
+
+	orders, err := client.FetchOrders(context.Background())
+	if err != nil {
+		t.Fatalf("FetchOrders failed: %v", err)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("Got %d orders, want 1", len(orders))
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if len(events) != 2 || events[0].Reason != "429 Too Many Requests" {
+		t.Errorf("retry events = %+v, want 2 starting with 429", events)
+	}
+}
+
+func TestClient_CapsRetryAfter(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pageJSON(1, 1, 1, "Patient Diner")))
+	}))
+	defer ts.Close()
+
+	client := fastClient(ts.URL)
+	var events []RetryEvent
+	client.OnRetry = func(e RetryEvent) { events = append(events, e) }
+
+	if _, err := client.FetchOrders(context.Background()); err != nil {
+		t.Fatalf("FetchOrders failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Wait != client.Retry.MaxDelay {
+		t.Errorf("retry events = %+v, want one wait of %s", events, client.Retry.MaxDelay)
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	if _, err := fastClient(ts.URL).FetchOrders(context.Background()); err == nil {
+		t.Fatal("Expected error on 403 response, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClient_FetchOrdersWithOptions_PartialAndResume(t *testing.T) {
+	failPage2 := true
+	var requested []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		requested = append(requested, page)
+		switch page {
+		case "1":
+			w.Write([]byte(pageJSON(1, 3, 3, "Page One")))
+		case "2":
+			if failPage2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(pageJSON(2, 3, 2, "Page Two")))
+		case "3":
+			w.Write([]byte(pageJSON(3, 3, 1, "Page Three")))
+		}
+	}))
+	defer ts.Close()
+
+	client := fastClient(ts.URL)
+	client.Retry.MaxRetries = 1
+
+	orders, err := client.FetchOrdersWithOptions(context.Background(), FetchOptions{})
+	var pageErr *PageError
+	if !errors.As(err, &pageErr) {
+		t.Fatalf("err = %v, want *PageError", err)
+	}
+	if pageErr.Page != 2 {
+		t.Errorf("PageError.Page = %d, want 2", pageErr.Page)
+	}
+	if len(orders) != 1 || orders[0].ID != "3" {
+		t.Errorf("partial orders = %+v, want order 3", orders)
+	}
+
+	failPage2 = false
+	requested = nil
+	orders, err = client.FetchOrdersWithOptions(context.Background(), FetchOptions{StartPage: pageErr.Page})
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if len(requested) != 2 || requested[0] != "2" {
+		t.Errorf("resumed pages = %v, want [2 3]", requested)
+	}
+	if len(orders) != 2 {
+		t.Errorf("resumed orders = %d, want 2", len(orders))
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"7", 7 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.value, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		got := p.backoff(attempt)
+		if got > max || got < max/2 {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, got, max/2, max)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package zomato
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// Embedded so region timezones resolve on systems without zoneinfo.
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the region timezone order times are shown in by
+// zomato.com in India. Order dates carry no zone, so they are interpreted in
+// the account's region timezone rather than the machine's.
+const DefaultTimezone = "Asia/Kolkata"
+
+// LoadLocation resolves an IANA timezone name, defaulting to DefaultTimezone.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name such as Asia/Dubai)", name)
+	}
+	return loc, nil
+}
+
+// location is the zone order dates are parsed in.
+func (c *Client) location() *time.Location {
+	return orDefaultLocation(c.Location)
+}
+
+func orDefaultLocation(loc *time.Location) *time.Location {
+	if loc != nil {
+		return loc
+	}
+	loc, err := LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Rezone reinterprets the wall-clock time of each order's PlacedAt in loc.
+// Zomato only reports the wall-clock time, so this corrects orders that were
+// parsed in the wrong zone without shifting the time the user saw.
+func Rezone(orders []Order, loc *time.Location) []Order {
+	out := make([]Order, len(orders))
+	for i, order := range orders {
+		if !order.PlacedAt.IsZero() {
+			t := order.PlacedAt
+			order.PlacedAt = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		}
+		out[i] = order
+	}
+	return out
+}
+
+// InLocation returns copies of orders with PlacedAt shown in loc. Unlike
+// Rezone the instant is kept; only weekday and hour buckets move.
+func InLocation(orders []Order, loc *time.Location) []Order {
+	out := make([]Order, len(orders))
+	for i, order := range orders {
+		if !order.PlacedAt.IsZero() {
+			order.PlacedAt = order.PlacedAt.In(loc)
+		}
+		out[i] = order
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package zomato
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRezone(t *testing.T) {
+	ist, err := LoadLocation("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Parsed on a machine in UTC-8 before region timezones existed.
+	pst := time.FixedZone("PST", -8*60*60)
+	orders := []Order{{ID: "1", PlacedAt: time.Date(2025, 12, 18, 20, 12, 0, 0, pst)}, {ID: "2"}}
+
+	got := Rezone(orders, ist)
+	if want := "2025-12-18T20:12:00+05:30"; got[0].PlacedAt.Format(time.RFC3339) != want {
+		t.Errorf("Rezone = %s, want %s", got[0].PlacedAt.Format(time.RFC3339), want)
+	}
+	if !got[1].PlacedAt.IsZero() {
+		t.Errorf("Rezone of a missing date = %v, want zero", got[1].PlacedAt)
+	}
+	if orders[0].PlacedAt.Location() != pst {
+		t.Error("Rezone modified its input")
+	}
+
+	shown := InLocation(got, time.UTC)
+	if !shown[0].PlacedAt.Equal(got[0].PlacedAt) || shown[0].PlacedAt.Hour() != 14 {
+		t.Errorf("InLocation = %v, want the same instant at 14:42 UTC", shown[0].PlacedAt)
+	}
+}
+
+func TestLoadLocationUnknown(t *testing.T) {
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Error("LoadLocation(Mars/Olympus) succeeded, want error")
+	}
+}